@@ -1,5 +1,7 @@
 package animations
 
+import "time"
+
 type Animation struct {
 	First        int
 	Last         int
@@ -35,3 +37,30 @@ func NewAnimation(first, last, step int, speed float32) *Animation {
 		first,
 	}
 }
+
+// Clock counts game ticks, the same clock Animation is advanced on, for things timed in milliseconds.
+type Clock struct {
+	ticks int
+	tps   int
+}
+
+func (c *Clock) Update() {
+	c.ticks += 1
+}
+
+// Milliseconds returns the time elapsed since the clock started, assuming tps ticks per second.
+func (c *Clock) Milliseconds() int {
+	return c.ticks * 1000 / c.tps
+}
+
+// Tick returns how much game time one tick covers.
+func (c *Clock) Tick() time.Duration {
+	return time.Second / time.Duration(c.tps)
+}
+
+func NewClock(tps int) *Clock {
+	return &Clock{
+		0,
+		tps,
+	}
+}
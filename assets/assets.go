@@ -0,0 +1,35 @@
+// Package assets loads game assets by logical name through an fs.FS and caches
+// them. A logical name is the slash-separated path under the asset root, e.g.
+// "images/ninga.png", regardless of where the root lives.
+package assets
+
+import (
+	"embed"
+	"io/fs"
+	"os"
+)
+
+// logical names of the assets the game uses
+const (
+	PlayerImage   = "images/ninga.png"
+	SkeletonImage = "images/skeleton.png"
+	PotionImage   = "images/LifePot.png"
+	SpawnMap      = "maps/spawn.json"
+)
+
+//go:embed images maps
+var embedded embed.FS
+
+// Embedded returns the assets compiled into the binary.
+func Embedded() fs.FS {
+	return embedded
+}
+
+// Open returns the asset directory dir if it exists, so assets can be edited or
+// modded without rebuilding, and the embedded assets otherwise.
+func Open(dir string) fs.FS {
+	if info, err := os.Stat(dir); err == nil && info.IsDir() {
+		return os.DirFS(dir)
+	}
+	return Embedded()
+}
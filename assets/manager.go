@@ -0,0 +1,229 @@
+package assets
+
+import (
+	"image"
+	"io/fs"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/FunctionPointerXDD/Trader/imagefile"
+	"github.com/FunctionPointerXDD/Trader/tilemap"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Manager loads assets from an fs.FS and caches them by logical name.
+//
+// DecodeImage, Tilemap and DecodeTilesets may be called from a loading goroutine.
+// Image, Tilesets and Poll must be called on the main thread.
+type Manager struct {
+	fsys fs.FS
+
+	mu             sync.Mutex
+	decoded        map[string]image.Image
+	images         map[string]*ebiten.Image
+	maps           map[string]*tilemap.Tilemap
+	tilesetSources map[string]*tilemap.TilesetSources
+	tilesets       map[string]*tilemap.Tilesets
+
+	// for Poll
+	deps     map[string][]string  // asset name -> files it was loaded from
+	modTimes map[string]time.Time // file -> modification time when it was loaded
+}
+
+func NewManager(fsys fs.FS) *Manager {
+	return &Manager{
+		fsys:           fsys,
+		decoded:        make(map[string]image.Image),
+		images:         make(map[string]*ebiten.Image),
+		maps:           make(map[string]*tilemap.Tilemap),
+		tilesetSources: make(map[string]*tilemap.TilesetSources),
+		tilesets:       make(map[string]*tilemap.Tilesets),
+		deps:           make(map[string][]string),
+		modTimes:       make(map[string]time.Time),
+	}
+}
+
+func (m *Manager) FS() fs.FS {
+	return m.fsys
+}
+
+// DecodeImage returns the decoded image called name, without creating an ebiten image.
+func (m *Manager) DecodeImage(name string) (image.Image, error) {
+	if img, ok := cached(m, m.decoded, name); ok {
+		return img, nil
+	}
+	img, err := imagefile.Decode(m.fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	m.watch(name, name)
+	store(m, m.decoded, name, img)
+	return img, nil
+}
+
+// Image returns the ebiten image called name.
+func (m *Manager) Image(name string) (*ebiten.Image, error) {
+	if img, ok := cached(m, m.images, name); ok {
+		return img, nil
+	}
+	decoded, err := m.DecodeImage(name)
+	if err != nil {
+		return nil, err
+	}
+	img := ebiten.NewImageFromImage(decoded)
+	store(m, m.images, name, img)
+	return img, nil
+}
+
+// Tilemap returns the map called name. Each call returns its own copy, so tiles
+// changed at runtime do not leak into the next scene that loads the map.
+func (m *Manager) Tilemap(name string) (*tilemap.Tilemap, error) {
+	if tiledMap, ok := cached(m, m.maps, name); ok {
+		return tiledMap.Clone(), nil
+	}
+	tiledMap, err := tilemap.Load(m.fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	m.watch(name, name)
+	store(m, m.maps, name, tiledMap)
+	return tiledMap.Clone(), nil
+}
+
+// DecodeTilesets returns the decoded tilesets used by the map called name.
+func (m *Manager) DecodeTilesets(name string) (*tilemap.TilesetSources, error) {
+	if sources, ok := cached(m, m.tilesetSources, name); ok {
+		return sources, nil
+	}
+	tiledMap, err := m.Tilemap(name)
+	if err != nil {
+		return nil, err
+	}
+	sources, err := tiledMap.DecodeTilesets(m.fsys)
+	if err != nil {
+		return nil, err
+	}
+	m.watch(name, sources.Files()...)
+	store(m, m.tilesetSources, name, sources)
+	return sources, nil
+}
+
+// Tilesets returns the tilesets used by the map called name, with their ebiten images.
+func (m *Manager) Tilesets(name string) (*tilemap.Tilesets, error) {
+	if tilesets, ok := cached(m, m.tilesets, name); ok {
+		return tilesets, nil
+	}
+	sources, err := m.DecodeTilesets(name)
+	if err != nil {
+		return nil, err
+	}
+	tilesets, err := sources.Build()
+	if err != nil {
+		return nil, err
+	}
+	store(m, m.tilesets, name, tilesets)
+	return tilesets, nil
+}
+
+// Poll checks the files behind every cached asset and drops the assets whose files
+// changed since they were loaded, so the next call loads them again. It returns the
+// names of the dropped assets, for the scenes using them to reload. The files stay
+// watched, so an asset whose reload fails, like a half-saved map, is reported
+// again on the next save.
+//
+// Files are compared by modification time, which never changes for embedded assets.
+func (m *Manager) Poll() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	changedFiles := make(map[string]bool)
+	for file, modTime := range m.modTimes {
+		info, err := fs.Stat(m.fsys, file)
+		if err != nil || info.ModTime().Equal(modTime) {
+			continue // 저장하는 도중에 잠깐 사라질 수 있으니 에러는 무시하고 다음에 다시 본다
+		}
+		m.modTimes[file] = info.ModTime()
+		changedFiles[file] = true
+	}
+	if len(changedFiles) == 0 {
+		return nil
+	}
+
+	changed := make([]string, 0)
+	for name, files := range m.deps {
+		if !slices.ContainsFunc(files, func(file string) bool { return changedFiles[file] }) {
+			continue
+		}
+		m.drop(name)
+		changed = append(changed, name)
+	}
+	slices.Sort(changed)
+	return changed
+}
+
+// Forget drops the cached assets called names, so the next call loads them from
+// their files again. Use it after an asset turned out to be broken, so a fixed file
+// is picked up without polling.
+func (m *Manager) Forget(names ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, name := range names {
+		m.drop(name)
+		delete(m.deps, name)
+	}
+	// 다른 에셋이 쓰지 않는 파일은 다시 읽을 때 수정 시간을 새로 잰다
+	for file := range m.modTimes {
+		used := false
+		for _, files := range m.deps {
+			if slices.Contains(files, file) {
+				used = true
+				break
+			}
+		}
+		if !used {
+			delete(m.modTimes, file)
+		}
+	}
+}
+
+// drop removes name from every cache, but not from deps. m.mu must be held.
+func (m *Manager) drop(name string) {
+	delete(m.decoded, name)
+	delete(m.images, name)
+	delete(m.maps, name)
+	delete(m.tilesetSources, name)
+	delete(m.tilesets, name)
+}
+
+// watch records that the asset called name was loaded from files.
+func (m *Manager) watch(name string, files ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, file := range files {
+		if !slices.Contains(m.deps[name], file) {
+			m.deps[name] = append(m.deps[name], file)
+		}
+		if _, ok := m.modTimes[file]; ok {
+			continue
+		}
+		if info, err := fs.Stat(m.fsys, file); err == nil {
+			m.modTimes[file] = info.ModTime()
+		}
+	}
+}
+
+func cached[T any](m *Manager, cache map[string]T, name string) (T, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	value, ok := cache[name]
+	return value, ok
+}
+
+func store[T any](m *Manager, cache map[string]T, name string, value T) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cache[name] = value
+}
@@ -0,0 +1,99 @@
+package assets
+
+import (
+	"slices"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func mapFile(gid string, modTime time.Time) *fstest.MapFile {
+	return &fstest.MapFile{
+		Data:    []byte(`{"tilewidth":16,"tileheight":16,"layers":[{"type":"tilelayer","width":1,"height":1,"data":[` + gid + `]}]}`),
+		ModTime: modTime,
+	}
+}
+
+func TestManagerTilemap(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	fsys := fstest.MapFS{"maps/a.json": mapFile("1", start)}
+	m := NewManager(fsys)
+
+	first, err := m.Tilemap("maps/a.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	first.Layers[0].Data[0] = 9
+	second, err := m.Tilemap("maps/a.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := second.Layers[0].Data[0]; got != 1 {
+		t.Errorf("second copy has gid %d, want 1: changes to a copy leaked into the cache", got)
+	}
+
+	// 캐시가 남아 있으면 파일이 바뀌어도 예전 맵을 준다
+	fsys["maps/a.json"] = mapFile("2", start)
+	if tm, _ := m.Tilemap("maps/a.json"); tm.Layers[0].Data[0] != 1 {
+		t.Errorf("got gid %d before Forget, want the cached 1", tm.Layers[0].Data[0])
+	}
+	m.Forget("maps/a.json")
+	if tm, _ := m.Tilemap("maps/a.json"); tm.Layers[0].Data[0] != 2 {
+		t.Errorf("got gid %d after Forget, want 2 from the file", tm.Layers[0].Data[0])
+	}
+}
+
+func TestManagerPoll(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	fsys := fstest.MapFS{
+		"maps/a.json": mapFile("1", start),
+		"maps/b.json": mapFile("1", start),
+	}
+	m := NewManager(fsys)
+	for _, name := range []string{"maps/a.json", "maps/b.json"} {
+		if _, err := m.Tilemap(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if changed := m.Poll(); len(changed) != 0 {
+		t.Errorf("Poll = %v with nothing changed, want none", changed)
+	}
+
+	fsys["maps/b.json"] = mapFile("3", start.Add(time.Second))
+	if changed := m.Poll(); !slices.Equal(changed, []string{"maps/b.json"}) {
+		t.Errorf("Poll = %v, want [maps/b.json]", changed)
+	}
+	if tm, _ := m.Tilemap("maps/b.json"); tm.Layers[0].Data[0] != 3 {
+		t.Errorf("got gid %d after Poll, want 3 from the file", tm.Layers[0].Data[0])
+	}
+	if changed := m.Poll(); len(changed) != 0 {
+		t.Errorf("second Poll = %v, want none", changed)
+	}
+}
+
+func TestManagerPollAfterFailedReload(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	fsys := fstest.MapFS{"maps/a.json": mapFile("1", start)}
+	m := NewManager(fsys)
+	if _, err := m.Tilemap("maps/a.json"); err != nil {
+		t.Fatal(err)
+	}
+
+	// 저장하다 만 파일
+	fsys["maps/a.json"] = &fstest.MapFile{Data: []byte(`{"tilewidth":16,"lay`), ModTime: start.Add(time.Second)}
+	if changed := m.Poll(); !slices.Equal(changed, []string{"maps/a.json"}) {
+		t.Fatalf("Poll = %v after a broken save, want [maps/a.json]", changed)
+	}
+	if _, err := m.Tilemap("maps/a.json"); err == nil {
+		t.Fatal("loaded a broken map")
+	}
+
+	fsys["maps/a.json"] = mapFile("2", start.Add(2*time.Second))
+	if changed := m.Poll(); !slices.Equal(changed, []string{"maps/a.json"}) {
+		t.Fatalf("Poll = %v after fixing the map, want [maps/a.json]", changed)
+	}
+	if tm, err := m.Tilemap("maps/a.json"); err != nil || tm.Layers[0].Data[0] != 2 {
+		t.Errorf("got %v, %v after fixing the map, want gid 2", tm, err)
+	}
+}
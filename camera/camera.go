@@ -1,31 +1,202 @@
 package camera
 
-import "math"
+import (
+	"image"
+	"math"
+	"math/rand"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+type Smoothing uint8
+
+const (
+	SmoothNone   Smoothing = iota // snap to the target every tick
+	SmoothLerp                    // ease toward the target, LerpSpeed per second
+	SmoothSpring                  // critically damped spring, SpringFrequency rad/s
+)
 
 type Camera struct {
-	X, Y float64
+	X, Y float64 // 화면에 그릴 때 월드에 더하는 오프셋 (흔들림 포함, 줌 적용 전)
+
+	Zoom            int // 정수 배율만 허용해서 픽셀이 깨지지 않게 한다
+	Smoothing       Smoothing
+	LerpSpeed       float64
+	SpringFrequency float64
+	DeadZone        image.Point // 타겟이 이 크기(뷰 픽셀) 안에서 움직이면 카메라는 가만히 있는다
+	LookAhead       float64     // 이동 방향으로 미리 보여주는 거리 (월드 픽셀)
+	MaxShake        float64     // trauma가 1일 때 흔들리는 최대 거리 (월드 픽셀)
+	TraumaDecay     float64     // 초당 줄어드는 trauma
+
+	centerX, centerY          float64 // 카메라가 보고 있는 월드 좌표 (흔들림 제외)
+	velX, velY                float64 // spring 속도
+	lookX, lookY              float64
+	targetX, targetY          float64
+	shakeX, shakeY            float64
+	trauma                    float64
+	following                 bool
+	screenWidth, screenHeight float64 // 논리 해상도, SetScreenSize로 설정
 }
 
 func NewCamera(x, y float64) *Camera {
 	return &Camera{
-		X: x,
-		Y: y,
+		X:               x,
+		Y:               y,
+		Zoom:            1,
+		Smoothing:       SmoothNone,
+		LerpSpeed:       8.0,
+		SpringFrequency: 10.0,
+		MaxShake:        6.0,
+		TraumaDecay:     1.5,
 	}
 }
 
+// SetScreenSize tells the camera the active logical resolution. Call it every tick,
+// since it changes with the window in expanding scale modes.
+func (c *Camera) SetScreenSize(width, height int) {
+	c.screenWidth = float64(width)
+	c.screenHeight = float64(height)
+}
+
+// viewSize returns the world pixels visible on screen at the current zoom.
+func (c *Camera) viewSize() (float64, float64) {
+	return c.screenWidth / float64(c.Zoom), c.screenHeight / float64(c.Zoom)
+}
+
+// SetZoom changes the zoom level, keeping it a positive integer for pixel-perfect rendering.
+func (c *Camera) SetZoom(zoom int) {
+	c.Zoom = max(zoom, 1)
+}
+
+// AddTrauma shakes the camera. Trauma adds up to 1 and wears off over time;
+// the shake grows with the square of trauma, so small hits stay subtle.
+func (c *Camera) AddTrauma(amount float64) {
+	c.trauma = math.Min(c.trauma+amount, 1.0)
+}
+
+// Update advances the screen shake. Call it once per tick, before FollowTarget.
+func (c *Camera) Update() {
+	dt := 1.0 / float64(ebiten.TPS())
+	c.trauma = math.Max(c.trauma-c.TraumaDecay*dt, 0.0)
+
+	shake := c.trauma * c.trauma * c.MaxShake
+	c.shakeX = math.Round(shake * (rand.Float64()*2.0 - 1.0))
+	c.shakeY = math.Round(shake * (rand.Float64()*2.0 - 1.0))
+}
+
 // Camera인스턴스에서 사용가능한 리시버 함수 (go 언어 기능)
-func (c *Camera) FollowTarget(targetX, targetY, screenWidth, screenHeight float64) {
+func (c *Camera) FollowTarget(targetX, targetY float64) {
 	/** 카메라는 실제로 존재하지 않는다.
 	배경자체를 카메라 오프셋 만큼 타켓이 이동하는 방향의 반대 방향으로 움직이면 카메라가 이동하는 효과를 얻을 수 있다. */
-	c.X = -targetX + screenWidth/2.0
-	c.Y = -targetY + screenHeight/2.0
+	dt := 1.0 / float64(ebiten.TPS())
+
+	if !c.following {
+		// first frame: start on the target instead of sweeping in from the origin
+		c.centerX, c.centerY = targetX, targetY
+		c.targetX, c.targetY = targetX, targetY
+		c.following = true
+	}
+
+	// look ahead in the direction the target is moving
+	lookX, lookY := 0.0, 0.0
+	if dx, dy := targetX-c.targetX, targetY-c.targetY; dx != 0.0 || dy != 0.0 {
+		length := math.Hypot(dx, dy)
+		lookX = dx / length * c.LookAhead
+		lookY = dy / length * c.LookAhead
+	} else {
+		lookX, lookY = c.lookX, c.lookY // standing still keeps the last look-ahead
+	}
+	ease := 1.0 - math.Exp(-4.0*dt)
+	c.lookX += (lookX - c.lookX) * ease
+	c.lookY += (lookY - c.lookY) * ease
+	c.targetX, c.targetY = targetX, targetY
+
+	// only chase the part of the target's movement that leaves the dead zone
+	goalX := deadZone(c.centerX, targetX+c.lookX, float64(c.DeadZone.X)/2.0)
+	goalY := deadZone(c.centerY, targetY+c.lookY, float64(c.DeadZone.Y)/2.0)
+
+	switch c.Smoothing {
+	case SmoothLerp:
+		ease := 1.0 - math.Exp(-c.LerpSpeed*dt)
+		c.centerX += (goalX - c.centerX) * ease
+		c.centerY += (goalY - c.centerY) * ease
+	case SmoothSpring:
+		c.centerX, c.velX = spring(c.centerX, c.velX, goalX, c.SpringFrequency, dt)
+		c.centerY, c.velY = spring(c.centerY, c.velY, goalY, c.SpringFrequency, dt)
+	default:
+		c.centerX, c.centerY = goalX, goalY
+	}
+
+	c.updateOffset()
 }
 
-/* 카메라가 배경 밖으로 벗어나지 않게 해주는 함수*/
-func (c *Camera) Constrain(tilemapWidthPixels, tilemapHeightPixels, screenWidth, screenHeight float64) {
-	c.X = math.Min(c.X, 0.0)
-	c.Y = math.Min(c.Y, 0.0)
+/* 카메라가 배경 밖으로 벗어나지 않게 해주는 함수 (mapBounds는 픽셀 단위, 무한 맵에서는 Min이 음수일 수 있다) */
+func (c *Camera) Constrain(mapBounds image.Rectangle) {
+	viewWidth, viewHeight := c.viewSize()
+
+	c.centerX = math.Max(c.centerX, float64(mapBounds.Min.X)+viewWidth/2.0)
+	c.centerY = math.Max(c.centerY, float64(mapBounds.Min.Y)+viewHeight/2.0)
+
+	c.centerX = math.Min(c.centerX, float64(mapBounds.Max.X)-viewWidth/2.0)
+	c.centerY = math.Min(c.centerY, float64(mapBounds.Max.Y)-viewHeight/2.0)
+
+	c.updateOffset()
+}
+
+// GeoM returns the transform from world pixels to screen pixels: the camera offset, then the zoom.
+func (c *Camera) GeoM() ebiten.GeoM {
+	geoM := ebiten.GeoM{}
+	geoM.Translate(c.X, c.Y)
+	geoM.Scale(float64(c.Zoom), float64(c.Zoom))
+	return geoM
+}
+
+// WorldToScreen converts world pixels to screen pixels.
+func (c *Camera) WorldToScreen(x, y float64) (float64, float64) {
+	return (x + c.X) * float64(c.Zoom), (y + c.Y) * float64(c.Zoom)
+}
+
+// ScreenToWorld converts screen pixels to world pixels. Screen pixels are in the logical
+// resolution returned by Layout, like ebiten.CursorPosition, so window scaling is already undone.
+func (c *Camera) ScreenToWorld(x, y float64) (float64, float64) {
+	return x/float64(c.Zoom) - c.X, y/float64(c.Zoom) - c.Y
+}
+
+// ViewRect returns the world pixels visible on screen, rounded outward.
+func (c *Camera) ViewRect() image.Rectangle {
+	viewWidth, viewHeight := c.viewSize()
+	return image.Rect(
+		int(math.Floor(-c.X)),
+		int(math.Floor(-c.Y)),
+		int(math.Ceil(-c.X+viewWidth)),
+		int(math.Ceil(-c.Y+viewHeight)),
+	)
+}
+
+// updateOffset snaps the offset to whole pixels, so smoothing never draws tiles between pixels.
+func (c *Camera) updateOffset() {
+	viewWidth, viewHeight := c.viewSize()
+	c.X = math.Round(-c.centerX+viewWidth/2.0) + c.shakeX
+	c.Y = math.Round(-c.centerY+viewHeight/2.0) + c.shakeY
+}
+
+// deadZone returns where the camera has to be for target to sit inside the dead zone around it.
+func deadZone(center, target, halfSize float64) float64 {
+	if target > center+halfSize {
+		return target - halfSize
+	}
+	if target < center-halfSize {
+		return target + halfSize
+	}
+	return center
+}
 
-	c.X = math.Max(c.X, screenWidth-tilemapWidthPixels)
-	c.Y = math.Max(c.Y, screenHeight-tilemapHeightPixels)
+// spring moves x toward goal along a critically damped spring, exact for any dt.
+func spring(x, v, goal, omega, dt float64) (float64, float64) {
+	offset := x - goal
+	exp := math.Exp(-omega * dt)
+	temp := (v + omega*offset) * dt
+	v = (v - omega*temp) * exp
+	offset = (offset + temp) * exp
+	return goal + offset, v
 }
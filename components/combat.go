@@ -1,25 +1,62 @@
 package components
 
+import "time"
+
 type Combat interface {
 	Health() int
 	AttackPower() int
+	// Attacking reports whether an attack is between its wind-up and the end of its recovery.
 	Attacking() bool
+	Phase() AttackPhase
+	// Attack starts an attack if the last one finished and its cooldown ran out.
 	Attack() bool
-	Update()
-	Damage(amount int)
+	// Strike reports, once per attack, that the attack just became active and should hit.
+	Strike() bool
+	Update(dt time.Duration)
+	// Damage takes health away unless the entity is invulnerable, and reports whether it did.
+	Damage(amount int) bool
+	Heal(amount int)
+	// InvulnerableFor returns how long the entity stays invulnerable after its last hit.
+	InvulnerableFor() time.Duration
+}
+
+type AttackPhase uint8
+
+const (
+	AttackReady    AttackPhase = iota
+	AttackWindUp               // telegraphing, can still be dodged
+	AttackActive               // the hit lands when this starts
+	AttackRecovery             // vulnerable, can't move on to the next attack
+)
+
+// AttackTiming is the lifecycle of one attack. Cooldown starts after the recovery.
+type AttackTiming struct {
+	WindUp   time.Duration
+	Active   time.Duration
+	Recovery time.Duration
+	Cooldown time.Duration
 }
 
 type BasicCombat struct {
-	health      int
-	attackPower int
-	attacking   bool
+	health          int
+	attackPower     int
+	timing          AttackTiming
+	invulnerability time.Duration // granted after every hit taken
+
+	phase            AttackPhase
+	phaseLeft        time.Duration
+	cooldownLeft     time.Duration
+	invulnerableLeft time.Duration
+	struck           bool // Strike is pending for the current attack
 }
 
-func NewBasicCombat(health, attackPower int) *BasicCombat {
+func NewBasicCombat(health, attackPower int, timing AttackTiming, invulnerability time.Duration) *BasicCombat {
 	return &BasicCombat{
-		health,
-		attackPower,
-		false,
+		health:          health,
+		attackPower:     attackPower,
+		timing:          timing,
+		invulnerability: invulnerability,
+		phase:           AttackReady,
 	}
 }
 
@@ -34,52 +71,88 @@ func (b *BasicCombat) Health() int {
 }
 
 // Damage implements [Combat].
-func (b *BasicCombat) Damage(amount int) {
+func (b *BasicCombat) Damage(amount int) bool {
+	if b.invulnerableLeft > 0 {
+		return false
+	}
 	b.health -= amount
+	b.invulnerableLeft = b.invulnerability
+	return true
 }
 
-func (b *BasicCombat) Attacking() bool {
-	return b.attacking
+// Heal implements [Combat].
+func (b *BasicCombat) Heal(amount int) {
+	b.health += amount
 }
 
-func (b *BasicCombat) Attack() bool {
-	b.attacking = true
-	return true
+// InvulnerableFor implements [Combat].
+func (b *BasicCombat) InvulnerableFor() time.Duration {
+	return max(b.invulnerableLeft, 0)
 }
 
-func (b *BasicCombat) Update() {
+// Attacking implements [Combat].
+func (b *BasicCombat) Attacking() bool {
+	return b.phase != AttackReady
 }
 
-// 컴파일러 에러 체크 확인용도(빠진 메서드가 있는지 확인)
-var _ Combat = (*BasicCombat)(nil)
-
-type EnemyCombat struct {
-	*BasicCombat
-	attackCooldown  int
-	timeSinceAttack int
+// Phase implements [Combat].
+func (b *BasicCombat) Phase() AttackPhase {
+	return b.phase
 }
 
-func NewEnemyCombat(health, attackPower, attackCooldown int) *EnemyCombat {
-	return &EnemyCombat{
-		NewBasicCombat(health, attackPower),
-		attackCooldown,
-		0,
+// Attack implements [Combat].
+func (b *BasicCombat) Attack() bool {
+	if b.phase != AttackReady || b.cooldownLeft > 0 {
+		return false
 	}
+	b.enter(AttackWindUp)
+	return true
 }
 
-func (e *EnemyCombat) Attack() bool {
-	if e.timeSinceAttack >= e.attackCooldown {
-		e.attacking = true
-		e.timeSinceAttack = 0
-		return true
+// Strike implements [Combat].
+func (b *BasicCombat) Strike() bool {
+	struck := b.struck
+	b.struck = false
+	return struck
+}
+
+// Update implements [Combat].
+func (b *BasicCombat) Update(dt time.Duration) {
+	b.invulnerableLeft -= dt
+	if b.phase == AttackReady {
+		b.cooldownLeft -= dt
+		return
+	}
+	b.phaseLeft -= dt
+	if b.phaseLeft <= 0 {
+		b.enter(b.phase + 1)
 	}
-	return false
 }
 
-// ebitengine 특성상 초당 60번 더해짐.. (1초에 60프레임 TPS:60)
-func (e *EnemyCombat) Update() {
-	e.timeSinceAttack += 1
+// enter switches to phase, skipping phases with no duration.
+func (b *BasicCombat) enter(phase AttackPhase) {
+	for {
+		switch phase {
+		case AttackWindUp:
+			b.phaseLeft = b.timing.WindUp
+		case AttackActive:
+			b.phaseLeft = b.timing.Active
+			b.struck = true
+		case AttackRecovery:
+			b.phaseLeft = b.timing.Recovery
+		default:
+			b.phase = AttackReady
+			b.phaseLeft = 0
+			b.cooldownLeft = b.timing.Cooldown
+			return
+		}
+		b.phase = phase
+		if b.phaseLeft > 0 {
+			return
+		}
+		phase++
+	}
 }
 
 // 컴파일러 에러 체크 확인용도(빠진 메서드가 있는지 확인)
-var _ Combat = (*EnemyCombat)(nil)
+var _ Combat = (*BasicCombat)(nil)
@@ -0,0 +1,117 @@
+package components
+
+import (
+	"testing"
+	"time"
+)
+
+const ms = time.Millisecond
+
+func TestBasicCombatPhases(t *testing.T) {
+	timing := AttackTiming{WindUp: 300 * ms, Active: 100 * ms, Recovery: 200 * ms, Cooldown: 400 * ms}
+
+	// 각 시점에서 한 번 Update한 뒤의 상태
+	tests := []struct {
+		name       string
+		timing     AttackTiming
+		steps      []time.Duration
+		wantPhase  AttackPhase
+		wantStrike bool
+	}{
+		{"no update", timing, nil, AttackWindUp, false},
+		{"still winding up", timing, []time.Duration{299 * ms}, AttackWindUp, false},
+		{"becomes active", timing, []time.Duration{300 * ms}, AttackActive, true},
+		{"active across small ticks", timing, []time.Duration{150 * ms, 150 * ms, 50 * ms}, AttackActive, true},
+		{"recovering", timing, []time.Duration{300 * ms, 100 * ms}, AttackRecovery, true},
+		{"done", timing, []time.Duration{300 * ms, 100 * ms, 200 * ms}, AttackReady, true},
+		{
+			"no wind-up strikes at once",
+			AttackTiming{Active: 100 * ms, Recovery: 100 * ms},
+			nil, AttackActive, true,
+		},
+		{
+			"no active phase still strikes",
+			AttackTiming{WindUp: 100 * ms, Recovery: 100 * ms},
+			[]time.Duration{100 * ms}, AttackRecovery, true,
+		},
+		{"all zero", AttackTiming{}, nil, AttackReady, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewBasicCombat(3, 1, tt.timing, 0)
+			if !c.Attack() {
+				t.Fatal("Attack refused while ready")
+			}
+			for _, dt := range tt.steps {
+				c.Update(dt)
+			}
+			if got := c.Phase(); got != tt.wantPhase {
+				t.Errorf("phase = %d, want %d", got, tt.wantPhase)
+			}
+			if got := c.Strike(); got != tt.wantStrike {
+				t.Errorf("Strike = %v, want %v", got, tt.wantStrike)
+			}
+			if c.Strike() {
+				t.Error("Strike reported twice for one attack")
+			}
+		})
+	}
+}
+
+func TestBasicCombatCooldown(t *testing.T) {
+	c := NewBasicCombat(3, 1, AttackTiming{Active: 100 * ms, Cooldown: 250 * ms}, 0)
+
+	if !c.Attack() {
+		t.Fatal("first Attack refused")
+	}
+	if c.Attack() {
+		t.Error("Attack accepted during an attack")
+	}
+	c.Update(100 * ms) // 공격 끝, 쿨다운 시작
+	if c.Attacking() {
+		t.Fatal("still attacking after the active phase")
+	}
+	if c.Attack() {
+		t.Error("Attack accepted right after the attack ended")
+	}
+	c.Update(200 * ms)
+	if c.Attack() {
+		t.Error("Attack accepted before the cooldown ran out")
+	}
+	c.Update(50 * ms)
+	if !c.Attack() {
+		t.Error("Attack refused after the cooldown ran out")
+	}
+}
+
+func TestBasicCombatInvulnerability(t *testing.T) {
+	c := NewBasicCombat(5, 1, AttackTiming{}, 500*ms)
+
+	tests := []struct {
+		dt         time.Duration // before the hit
+		wantHit    bool
+		wantHealth int
+	}{
+		{0, true, 4},
+		{100 * ms, false, 4},
+		{399 * ms, false, 4},
+		{1 * ms, true, 3},
+		{0, false, 3},
+	}
+	for i, tt := range tests {
+		c.Update(tt.dt)
+		if got := c.Damage(1); got != tt.wantHit {
+			t.Errorf("hit %d: Damage = %v, want %v", i, got, tt.wantHit)
+		}
+		if got := c.Health(); got != tt.wantHealth {
+			t.Errorf("hit %d: health = %d, want %d", i, got, tt.wantHealth)
+		}
+	}
+	if got := c.InvulnerableFor(); got != 500*ms {
+		t.Errorf("InvulnerableFor = %v right after a hit, want 500ms", got)
+	}
+	c.Update(time.Second)
+	if got := c.InvulnerableFor(); got != 0 {
+		t.Errorf("InvulnerableFor = %v long after a hit, want 0", got)
+	}
+}
@@ -0,0 +1,167 @@
+package components
+
+import (
+	"image"
+
+	"github.com/FunctionPointerXDD/Trader/animations"
+	"github.com/FunctionPointerXDD/Trader/nav"
+	"github.com/FunctionPointerXDD/Trader/spritesheet"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Position is the top-left corner of the entity, in world pixels.
+type Position struct {
+	X, Y float64
+}
+
+// Velocity is the movement applied each tick, in world pixels.
+type Velocity struct {
+	Dx, Dy float64
+}
+
+// Sprite is what the entity looks like. Src is the part of Img drawn when the
+// entity has no Animation.
+type Sprite struct {
+	Img *ebiten.Image
+	Src image.Rectangle
+}
+
+type Facing uint8
+
+const (
+	Down Facing = iota
+	Up
+	Left
+	Right
+)
+
+// Animation picks the sprite frame from the direction the entity is moving in.
+type Animation struct {
+	Sheet      *spritesheet.SpriteSheet
+	Animations map[Facing]*animations.Animation
+}
+
+// FacingOf returns the direction of moving by dx, dy, preferring the horizontal.
+// It reports false when standing still.
+func FacingOf(dx, dy float64) (Facing, bool) {
+	switch {
+	case dx > 0:
+		return Right, true
+	case dx < 0:
+		return Left, true
+	case dy > 0:
+		return Down, true
+	case dy < 0:
+		return Up, true
+	}
+	return Down, false
+}
+
+// Vector returns the unit step in the direction f.
+func (f Facing) Vector() (float64, float64) {
+	switch f {
+	case Up:
+		return 0, -1
+	case Left:
+		return -1, 0
+	case Right:
+		return 1, 0
+	}
+	return 0, 1
+}
+
+// Active returns the animation for moving by dx, dy, or nil when standing still.
+func (a *Animation) Active(dx, dy float64) *animations.Animation {
+	facing, ok := FacingOf(dx, dy)
+	if !ok {
+		return nil
+	}
+	return a.Animations[facing]
+}
+
+// Collider is the entity's body, from its Position. It blocks movement into the
+// map's colliders and is the area used for touching and picking things up.
+type Collider struct {
+	Width, Height float64
+}
+
+func (c *Collider) Rect(pos *Position) image.Rectangle {
+	return image.Rect(int(pos.X), int(pos.Y), int(pos.X+c.Width), int(pos.Y+c.Height))
+}
+
+// Pickup is consumed by the player on touch.
+type Pickup struct {
+	Heal int
+}
+
+type AIState uint8
+
+const (
+	AIIdle   AIState = iota // standing at home, looking around
+	AIPatrol                // walking to a random point near home
+	AIChase                 // running at the player, or to where it last saw them
+	AIAttack                // in reach of the player, swinging
+	AIFlee                  // hurt too badly, running away from the player
+	AIReturn                // lost the player or went too far, walking home
+)
+
+func (s AIState) String() string {
+	switch s {
+	case AIIdle:
+		return "idle"
+	case AIPatrol:
+		return "patrol"
+	case AIChase:
+		return "chase"
+	case AIAttack:
+		return "attack"
+	case AIFlee:
+		return "flee"
+	case AIReturn:
+		return "return"
+	}
+	return "unknown"
+}
+
+// AIParams tune an AI brain, usually per enemy type. Distances are in world pixels
+// between entity centers, speeds in pixels per tick.
+type AIParams struct {
+	Speed         float64
+	PatrolSpeed   float64
+	PatrolRadius  float64 // how far from home patrols wander, 0 to stand still
+	AggroRadius   float64 // sees the player within this distance, if nothing blocks the view
+	LeashDistance float64 // gives up the chase this far from home
+	AttackRange   float64
+	FleeHealth    int // flees at or below this health, 0 to fight to the end
+}
+
+// AI drives an entity that is not controlled by the player.
+type AI struct {
+	AIParams
+	State        AIState
+	StateTicks   int     // ticks spent in State
+	HomeX, HomeY float64 // center of the entity where it spawned
+	GoalX, GoalY float64 // patrol point, or where the player was last seen
+	IdleTicks    int     // how long to idle before the next patrol
+}
+
+// SetState switches to state and restarts the state timer.
+func (a *AI) SetState(state AIState) {
+	a.State = state
+	a.StateTicks = 0
+}
+
+// Path is the route an entity is walking along, planned on the navigation grid.
+type Path struct {
+	Waypoints []image.Point // world pixels
+	Next      int           // index of the waypoint being walked to
+	GoalCell  image.Point   // the cell the path was planned to
+	Planned   bool          // false until planned; a planned path without waypoints is unreachable
+	Age       int           // ticks since planned
+	Search    *nav.Search   // planning in progress, carried over between ticks
+}
+
+// PlayerInput marks the entity moved by the keyboard and mouse.
+type PlayerInput struct {
+	Speed float64 // pixels per tick
+}
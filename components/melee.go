@@ -0,0 +1,87 @@
+package components
+
+import (
+	"image"
+
+	"github.com/FunctionPointerXDD/Trader/ecs"
+)
+
+// Team keeps hitboxes from hurting their own side.
+type Team uint8
+
+const (
+	TeamPlayer Team = iota
+	TeamEnemy
+)
+
+// Hurtbox is where the entity can be hit, from its Position.
+type Hurtbox struct {
+	OffsetX, OffsetY float64
+	Width, Height    float64
+	Team             Team
+}
+
+func (h *Hurtbox) Rect(pos *Position) image.Rectangle {
+	x, y := pos.X+h.OffsetX, pos.Y+h.OffsetY
+	return image.Rect(int(x), int(y), int(x+h.Width), int(y+h.Height))
+}
+
+// Melee is the shape of an entity's swing. The hitbox sticks Reach pixels out of
+// the side of the body the entity faces and is Width pixels across, centered on
+// that side. Knockback is the push given to whatever it hits, in pixels per tick.
+type Melee struct {
+	Reach     float64
+	Width     float64
+	Knockback float64
+}
+
+// Area returns the rectangle a swing from body toward facing covers.
+func (m *Melee) Area(body image.Rectangle, facing Facing) image.Rectangle {
+	reach, half := int(m.Reach), int(m.Width/2)
+	mid := body.Min.Add(body.Max).Div(2)
+	switch facing {
+	case Up:
+		return image.Rect(mid.X-half, body.Min.Y-reach, mid.X+half, body.Min.Y)
+	case Left:
+		return image.Rect(body.Min.X-reach, mid.Y-half, body.Min.X, mid.Y+half)
+	case Right:
+		return image.Rect(body.Max.X, mid.Y-half, body.Max.X+reach, mid.Y+half)
+	}
+	return image.Rect(mid.X-half, body.Max.Y, mid.X+half, body.Max.Y+reach)
+}
+
+// Hitbox is a swing in progress. It lives on the attacker for the active phase of
+// its attack and moves with it, but keeps the direction it was swung in.
+type Hitbox struct {
+	Rect      image.Rectangle
+	Facing    Facing
+	Team      Team
+	Damage    int
+	Knockback float64
+	hit       map[ecs.Entity]bool
+}
+
+func NewHitbox(facing Facing, team Team, damage int, knockback float64) *Hitbox {
+	return &Hitbox{
+		Facing:    facing,
+		Team:      team,
+		Damage:    damage,
+		Knockback: knockback,
+		hit:       make(map[ecs.Entity]bool),
+	}
+}
+
+// Hit records that the swing reached e, and reports whether it is the first time.
+// A swing hits each entity once, however long they overlap.
+func (h *Hitbox) Hit(e ecs.Entity) bool {
+	if h.hit[e] {
+		return false
+	}
+	h.hit[e] = true
+	return true
+}
+
+// Knockback is a push that fades out, added to the entity's velocity when it moves.
+type Knockback struct {
+	Dx, Dy float64
+}
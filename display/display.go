@@ -0,0 +1,167 @@
+package display
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image/color"
+	"math"
+	"os"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+type ScaleMode uint8
+
+const (
+	ScalePixelPerfect ScaleMode = iota // largest integer scale that fits, letterboxed
+	ScaleFit                           // fills the window keeping the aspect ratio, filtered
+	ScaleExpand                        // integer scale, the logical size grows to fill the window
+)
+
+var scaleModeNames = map[ScaleMode]string{
+	ScalePixelPerfect: "pixel_perfect",
+	ScaleFit:          "fit",
+	ScaleExpand:       "expand",
+}
+
+func (s ScaleMode) MarshalText() ([]byte, error) {
+	name, ok := scaleModeNames[s]
+	if !ok {
+		return nil, fmt.Errorf("unknown scale mode %d", s)
+	}
+	return []byte(name), nil
+}
+
+func (s *ScaleMode) UnmarshalText(text []byte) error {
+	for mode, name := range scaleModeNames {
+		if name == string(text) {
+			*s = mode
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown scale mode %q", text)
+}
+
+type Config struct {
+	Width       int       `json:"width"`  // logical resolution the game is drawn at
+	Height      int       `json:"height"` // (the minimum one in ScaleExpand)
+	ScaleMode   ScaleMode `json:"scale_mode"`
+	WindowScale int       `json:"window_scale"` // initial window size, in multiples of the logical resolution
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Width:       320,
+		Height:      240,
+		ScaleMode:   ScalePixelPerfect,
+		WindowScale: 2,
+	}
+}
+
+// LoadConfig reads the display config from a JSON file. Settings the file leaves out,
+// or a missing file, fall back to DefaultConfig.
+func LoadConfig(path string) (Config, error) {
+	config := DefaultConfig()
+
+	contents, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return config, nil
+	}
+	if err != nil {
+		return config, err
+	}
+
+	if err := json.Unmarshal(contents, &config); err != nil {
+		return config, fmt.Errorf("%s: %w", path, err)
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.WindowScale <= 0 {
+		return config, fmt.Errorf("%s: width, height and window_scale must be positive", path)
+	}
+
+	return config, nil
+}
+
+// Display owns the logical resolution and how it is scaled to the window.
+type Display struct {
+	config        Config
+	width, height int         // active logical resolution
+	ebitenGeoM    ebiten.GeoM // how ebiten would have scaled the last frame
+	screenGeoM    ebiten.GeoM // how we scaled it
+}
+
+func NewDisplay(config Config) *Display {
+	return &Display{
+		config: config,
+		width:  config.Width,
+		height: config.Height,
+	}
+}
+
+func (d *Display) Config() Config {
+	return d.config
+}
+
+// Size returns the active logical resolution, which every scene draws at.
+func (d *Display) Size() (int, int) {
+	return d.width, d.height
+}
+
+// Layout implements the ebiten.Game method of the same name.
+func (d *Display) Layout(outsideWidth, outsideHeight int) (int, int) {
+	if d.config.ScaleMode == ScaleExpand {
+		scale := max(min(outsideWidth/d.config.Width, outsideHeight/d.config.Height), 1)
+		// 내림해서 정수 배율을 유지하고, 남는 몇 픽셀은 DrawFinalScreen에서 레터박스로 채운다
+		d.width = max(outsideWidth/scale, d.config.Width)
+		d.height = max(outsideHeight/scale, d.config.Height)
+		return d.width, d.height
+	}
+
+	d.width, d.height = d.config.Width, d.config.Height
+	return d.width, d.height
+}
+
+// DrawFinalScreen implements ebiten.FinalScreenDrawer.
+func (d *Display) DrawFinalScreen(screen ebiten.FinalScreen, offscreen *ebiten.Image, geoM ebiten.GeoM) {
+	d.ebitenGeoM = geoM
+	d.screenGeoM = geoM
+
+	opts := ebiten.DrawImageOptions{}
+
+	switch d.config.ScaleMode {
+	case ScalePixelPerfect, ScaleExpand:
+		// round ebiten's fit-to-window scale down to an integer and centre the result
+		scale := math.Max(math.Floor(geoM.Element(0, 0)), 1.0)
+		w := float64(offscreen.Bounds().Dx()) * scale
+		h := float64(offscreen.Bounds().Dy()) * scale
+		d.screenGeoM = ebiten.GeoM{}
+		d.screenGeoM.Scale(scale, scale)
+		d.screenGeoM.Translate(
+			math.Floor((float64(screen.Bounds().Dx())-w)/2.0),
+			math.Floor((float64(screen.Bounds().Dy())-h)/2.0),
+		)
+		opts.Filter = ebiten.FilterNearest
+	default:
+		opts.Filter = ebiten.FilterLinear
+	}
+
+	screen.Fill(color.Black) // letterbox
+	opts.GeoM = d.screenGeoM
+	screen.DrawImage(offscreen, &opts)
+}
+
+// CursorPosition returns the cursor in logical pixels. Use it instead of ebiten.CursorPosition,
+// which assumes ebiten's own scaling rather than the one DrawFinalScreen applied.
+func (d *Display) CursorPosition() (int, int) {
+	x, y := ebiten.CursorPosition()
+
+	windowX, windowY := d.ebitenGeoM.Apply(float64(x)+0.5, float64(y)+0.5)
+	inverse := d.screenGeoM
+	if !inverse.IsInvertible() {
+		return x, y
+	}
+	inverse.Invert()
+	logicalX, logicalY := inverse.Apply(windowX, windowY)
+
+	return int(math.Floor(logicalX)), int(math.Floor(logicalY))
+}
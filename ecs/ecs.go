@@ -0,0 +1,58 @@
+// Package ecs is a small entity-component store. Entities are ids; each component
+// type lives in its own Store, and systems iterate the entities that have a set of
+// components with Each, Join2 and Join3.
+package ecs
+
+type Entity uint32
+
+// remover is implemented by every Store, so World can clean up despawned entities.
+type remover interface {
+	Remove(e Entity)
+}
+
+type World struct {
+	next   Entity
+	alive  map[Entity]struct{}
+	dead   []Entity
+	stores []remover
+}
+
+func NewWorld() *World {
+	return &World{
+		next:  1, // 0은 "엔티티 없음"으로 남겨둔다
+		alive: make(map[Entity]struct{}),
+	}
+}
+
+func (w *World) Spawn() Entity {
+	e := w.next
+	w.next++
+	w.alive[e] = struct{}{}
+	return e
+}
+
+// Despawn marks e for removal. Its components stay until Flush, so systems can
+// despawn entities while iterating.
+func (w *World) Despawn(e Entity) {
+	if _, ok := w.alive[e]; !ok {
+		return
+	}
+	delete(w.alive, e)
+	w.dead = append(w.dead, e)
+}
+
+func (w *World) Alive(e Entity) bool {
+	_, ok := w.alive[e]
+	return ok
+}
+
+// Flush removes the components of every entity despawned since the last Flush.
+// Call it once per tick, after the systems ran.
+func (w *World) Flush() {
+	for _, e := range w.dead {
+		for _, store := range w.stores {
+			store.Remove(e)
+		}
+	}
+	w.dead = w.dead[:0]
+}
@@ -0,0 +1,110 @@
+package ecs
+
+// Store holds one component type, packed densely for iteration. Components with
+// state are usually stored as pointers (Store[*Position]) so Get can change them.
+type Store[T any] struct {
+	world    *World
+	entities []Entity
+	values   []T
+	index    map[Entity]int
+}
+
+// NewStore creates a store whose components are removed when their entity despawns.
+func NewStore[T any](w *World) *Store[T] {
+	s := &Store[T]{
+		world: w,
+		index: make(map[Entity]int),
+	}
+	w.stores = append(w.stores, s)
+	return s
+}
+
+// Add sets e's component, replacing the one it had.
+func (s *Store[T]) Add(e Entity, value T) {
+	if i, ok := s.index[e]; ok {
+		s.values[i] = value
+		return
+	}
+	s.index[e] = len(s.values)
+	s.entities = append(s.entities, e)
+	s.values = append(s.values, value)
+}
+
+func (s *Store[T]) Get(e Entity) (T, bool) {
+	i, ok := s.index[e]
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	return s.values[i], true
+}
+
+func (s *Store[T]) Has(e Entity) bool {
+	_, ok := s.index[e]
+	return ok
+}
+
+// Remove deletes e's component by moving the last one into its slot.
+func (s *Store[T]) Remove(e Entity) {
+	i, ok := s.index[e]
+	if !ok {
+		return
+	}
+	last := len(s.values) - 1
+	s.entities[i] = s.entities[last]
+	s.values[i] = s.values[last]
+	s.index[s.entities[i]] = i
+
+	var zero T
+	s.values[last] = zero
+	s.entities = s.entities[:last]
+	s.values = s.values[:last]
+	delete(s.index, e)
+}
+
+func (s *Store[T]) Len() int {
+	return len(s.values)
+}
+
+// First returns any entity with the component, for stores that hold a single one
+// such as the player.
+func (s *Store[T]) First() (Entity, T, bool) {
+	if len(s.values) == 0 {
+		var zero T
+		return 0, zero, false
+	}
+	return s.entities[0], s.values[0], true
+}
+
+// Each calls fn for every live entity with the component. Components added during
+// the loop are not visited.
+func (s *Store[T]) Each(fn func(e Entity, value T)) {
+	for i, n := 0, len(s.values); i < n; i++ {
+		if s.world.Alive(s.entities[i]) {
+			fn(s.entities[i], s.values[i])
+		}
+	}
+}
+
+// Join2 calls fn for every live entity that has both components. It walks a, so
+// pass the smaller store first.
+func Join2[A, B any](a *Store[A], b *Store[B], fn func(e Entity, a A, b B)) {
+	a.Each(func(e Entity, valueA A) {
+		if valueB, ok := b.Get(e); ok {
+			fn(e, valueA, valueB)
+		}
+	})
+}
+
+// Join3 calls fn for every live entity that has all three components.
+func Join3[A, B, C any](a *Store[A], b *Store[B], c *Store[C], fn func(e Entity, a A, b B, c C)) {
+	a.Each(func(e Entity, valueA A) {
+		valueB, ok := b.Get(e)
+		if !ok {
+			return
+		}
+		if valueC, ok := c.Get(e); ok {
+			fn(e, valueA, valueB, valueC)
+		}
+	})
+}
@@ -1,9 +1,75 @@
 package entities
 
-import "github.com/FunctionPointerXDD/Trader/components"
+import (
+	"image"
+	"time"
 
-type Enemy struct {
-	*Sprite       // struct embeding -> Enemy를 Sprite처럼 사용가능.
-	FollowsPlayer bool
-	CombatComp    *components.EnemyCombat
+	"github.com/FunctionPointerXDD/Trader/components"
+	"github.com/FunctionPointerXDD/Trader/constants"
+	"github.com/FunctionPointerXDD/Trader/ecs"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// EnemyType is the stats and behaviour shared by every enemy of a kind.
+type EnemyType struct {
+	AI              components.AIParams
+	Health          int
+	AttackPower     int
+	Attack          components.AttackTiming
+	Melee           components.Melee
+	Invulnerability time.Duration // after being hit
+}
+
+// EnemyTypes are the enemies a map can place, by the enemy_type property.
+var EnemyTypes = map[string]EnemyType{
+	"skeleton": {
+		AI: components.AIParams{
+			Speed:         0.8,
+			PatrolSpeed:   0.4,
+			PatrolRadius:  constants.Tilesize * 3,
+			AggroRadius:   constants.Tilesize * 6,
+			LeashDistance: constants.Tilesize * 12,
+			AttackRange:   constants.Tilesize + 2,
+			FleeHealth:    0,
+		},
+		Health:      3,
+		AttackPower: 1,
+		Attack: components.AttackTiming{
+			WindUp:   300 * time.Millisecond,
+			Active:   100 * time.Millisecond,
+			Recovery: 300 * time.Millisecond,
+			Cooldown: 400 * time.Millisecond,
+		},
+		Melee: components.Melee{
+			Reach:     10,
+			Width:     constants.Tilesize + 4,
+			Knockback: 3,
+		},
+		Invulnerability: 200 * time.Millisecond,
+	},
+}
+
+func NewEnemy(w *World, img *ebiten.Image, x, y float64, enemyType EnemyType) ecs.Entity {
+	e := w.Spawn()
+	w.Positions.Add(e, &components.Position{X: x, Y: y})
+	w.Velocities.Add(e, &components.Velocity{})
+	w.Sprites.Add(e, &components.Sprite{Img: img, Src: image.Rect(0, 0, constants.Tilesize, constants.Tilesize)})
+	w.Facings.Add(e, components.Down)
+	w.Combat.Add(e, components.NewBasicCombat(enemyType.Health, enemyType.AttackPower, enemyType.Attack, enemyType.Invulnerability))
+	melee := enemyType.Melee
+	w.Melee.Add(e, &melee)
+	w.Hurtboxes.Add(e, &components.Hurtbox{
+		Width:  constants.Tilesize,
+		Height: constants.Tilesize,
+		Team:   components.TeamEnemy,
+	})
+	w.Colliders.Add(e, &components.Collider{Width: constants.Tilesize, Height: constants.Tilesize})
+	w.AI.Add(e, &components.AI{
+		AIParams: enemyType.AI,
+		State:    components.AIIdle,
+		HomeX:    x + constants.Tilesize/2,
+		HomeY:    y + constants.Tilesize/2,
+	})
+	w.Paths.Add(e, &components.Path{})
+	return e
 }
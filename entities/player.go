@@ -1,38 +1,60 @@
 package entities
 
 import (
+	"image"
+	"time"
+
 	"github.com/FunctionPointerXDD/Trader/animations"
 	"github.com/FunctionPointerXDD/Trader/components"
+	"github.com/FunctionPointerXDD/Trader/constants"
+	"github.com/FunctionPointerXDD/Trader/ecs"
+	"github.com/FunctionPointerXDD/Trader/spritesheet"
+	"github.com/hajimehoshi/ebiten/v2"
 )
 
-type PlayerState uint8
+// 클릭하면 바로 때리고, 맞으면 잠깐 무적
+var playerAttack = components.AttackTiming{
+	Active:   100 * time.Millisecond,
+	Recovery: 150 * time.Millisecond,
+	Cooldown: 150 * time.Millisecond,
+}
 
-const (
-	Down PlayerState = iota
-	Up
-	Left
-	Right
-)
+const playerInvulnerability = time.Second
 
-type Player struct {
-	*Sprite
-	Health     uint
-	Animations map[PlayerState]*animations.Animation
-	CombatComp *components.BasicCombat
+// 앞으로 휘두르는 짧은 베기
+var playerMelee = components.Melee{
+	Reach:     14,
+	Width:     constants.Tilesize + 4,
+	Knockback: 4,
 }
 
-func (p *Player) ActiveAnimation(dx, dy int) *animations.Animation {
-	if dx > 0 {
-		return p.Animations[Right]
-	}
-	if dx < 0 {
-		return p.Animations[Left]
-	}
-	if dy > 0 {
-		return p.Animations[Down]
-	}
-	if dy < 0 {
-		return p.Animations[Up]
-	}
-	return nil // No update in Game Update function
+func NewPlayer(w *World, img *ebiten.Image, x, y float64) ecs.Entity {
+	e := w.Spawn()
+	w.Positions.Add(e, &components.Position{X: x, Y: y})
+	w.Velocities.Add(e, &components.Velocity{})
+	w.Sprites.Add(e, &components.Sprite{Img: img, Src: image.Rect(0, 0, constants.Tilesize, constants.Tilesize)})
+	w.Animations.Add(e, &components.Animation{
+		Sheet: spritesheet.NewSpriteSheet(4, 7, constants.Tilesize),
+		Animations: map[components.Facing]*animations.Animation{
+			components.Up:    animations.NewAnimation(5, 13, 4, 20),
+			components.Down:  animations.NewAnimation(4, 12, 4, 20),
+			components.Left:  animations.NewAnimation(6, 14, 4, 20),
+			components.Right: animations.NewAnimation(7, 15, 4, 20),
+		},
+	})
+	w.Facings.Add(e, components.Down)
+	w.Combat.Add(e, components.NewBasicCombat(3, 1, playerAttack, playerInvulnerability))
+	melee := playerMelee
+	w.Melee.Add(e, &melee)
+	// 몸보다 조금 작게 잡아서 스치는 공격은 피한다
+	w.Hurtboxes.Add(e, &components.Hurtbox{
+		OffsetX: 3,
+		OffsetY: 3,
+		Width:   constants.Tilesize - 6,
+		Height:  constants.Tilesize - 3,
+		Team:    components.TeamPlayer,
+	})
+	w.Colliders.Add(e, &components.Collider{Width: constants.Tilesize, Height: constants.Tilesize})
+	w.PlayerInput.Add(e, &components.PlayerInput{Speed: 2})
+	return e
 }
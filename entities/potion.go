@@ -1,7 +1,19 @@
 package entities
 
-type Potion struct {
-	*Sprite
-	AmtHeal uint
-	IsUsed  bool
+import (
+	"image"
+
+	"github.com/FunctionPointerXDD/Trader/components"
+	"github.com/FunctionPointerXDD/Trader/constants"
+	"github.com/FunctionPointerXDD/Trader/ecs"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+func NewPotion(w *World, img *ebiten.Image, x, y float64, heal int) ecs.Entity {
+	e := w.Spawn()
+	w.Positions.Add(e, &components.Position{X: x, Y: y})
+	w.Sprites.Add(e, &components.Sprite{Img: img, Src: image.Rect(0, 0, constants.Tilesize, constants.Tilesize)})
+	w.Colliders.Add(e, &components.Collider{Width: constants.Tilesize, Height: constants.Tilesize})
+	w.Pickups.Add(e, &components.Pickup{Heal: heal})
+	return e
 }
@@ -1,8 +0,0 @@
-package entities
-
-import "github.com/hajimehoshi/ebiten/v2"
-
-type Sprite struct {
-	Img          *ebiten.Image
-	X, Y, Dx, Dy float64
-}
@@ -0,0 +1,59 @@
+package entities
+
+import (
+	"github.com/FunctionPointerXDD/Trader/components"
+	"github.com/FunctionPointerXDD/Trader/ecs"
+)
+
+// World holds every entity in a scene and a store per component type. A kind of
+// thing (player, enemy, potion) is a constructor that picks its components.
+type World struct {
+	*ecs.World
+	Positions   *ecs.Store[*components.Position]
+	Velocities  *ecs.Store[*components.Velocity]
+	Sprites     *ecs.Store[*components.Sprite]
+	Animations  *ecs.Store[*components.Animation]
+	Facings     *ecs.Store[components.Facing]
+	Combat      *ecs.Store[components.Combat]
+	Melee       *ecs.Store[*components.Melee]
+	Hitboxes    *ecs.Store[*components.Hitbox]
+	Hurtboxes   *ecs.Store[*components.Hurtbox]
+	Knockbacks  *ecs.Store[*components.Knockback]
+	Colliders   *ecs.Store[*components.Collider]
+	Pickups     *ecs.Store[*components.Pickup]
+	AI          *ecs.Store[*components.AI]
+	Paths       *ecs.Store[*components.Path]
+	PlayerInput *ecs.Store[*components.PlayerInput]
+}
+
+func NewWorld() *World {
+	w := ecs.NewWorld()
+	return &World{
+		World:       w,
+		Positions:   ecs.NewStore[*components.Position](w),
+		Velocities:  ecs.NewStore[*components.Velocity](w),
+		Sprites:     ecs.NewStore[*components.Sprite](w),
+		Animations:  ecs.NewStore[*components.Animation](w),
+		Facings:     ecs.NewStore[components.Facing](w),
+		Combat:      ecs.NewStore[components.Combat](w),
+		Melee:       ecs.NewStore[*components.Melee](w),
+		Hitboxes:    ecs.NewStore[*components.Hitbox](w),
+		Hurtboxes:   ecs.NewStore[*components.Hurtbox](w),
+		Knockbacks:  ecs.NewStore[*components.Knockback](w),
+		Colliders:   ecs.NewStore[*components.Collider](w),
+		Pickups:     ecs.NewStore[*components.Pickup](w),
+		AI:          ecs.NewStore[*components.AI](w),
+		Paths:       ecs.NewStore[*components.Path](w),
+		PlayerInput: ecs.NewStore[*components.PlayerInput](w),
+	}
+}
+
+// Player returns the player entity and its position.
+func (w *World) Player() (ecs.Entity, *components.Position, bool) {
+	e, _, ok := w.PlayerInput.First()
+	if !ok {
+		return 0, nil, false
+	}
+	pos, ok := w.Positions.Get(e)
+	return e, pos, ok
+}
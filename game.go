@@ -1,55 +1,86 @@
 package main
 
 import (
+	"github.com/FunctionPointerXDD/Trader/assets"
+	"github.com/FunctionPointerXDD/Trader/display"
 	"github.com/FunctionPointerXDD/Trader/scenes"
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
+// reloadInterval is how often, in ticks, assets are checked for changes in hot reload mode.
+const reloadInterval = 30
+
 type Game struct {
-	sceneMap      map[scenes.SceneId]scenes.Scene
-	activeSceneId scenes.SceneId
+	sceneManager *scenes.Manager
+	display      *display.Display
+	assets       *assets.Manager
+	hotReload    bool // 개발 모드: 에셋 파일이 바뀌면 게임을 끄지 않고 다시 읽는다
+	ticks        int
 }
 
-func NewGame() *Game {
-	sceneMap := map[scenes.SceneId]scenes.Scene{
-		scenes.GameSceneId:  scenes.NewGameScene(),
-		scenes.StartSceneId: scenes.NewStartScene(),
-		scenes.PauseSceneId: scenes.NewPauseScene(),
+func NewGame(display *display.Display, manager *assets.Manager) (*Game, error) {
+	registry := scenes.NewRegistry()
+	registry.Register(scenes.GameSceneId, func(payload any) (scenes.Scene, error) {
+		data, err := scenes.PayloadAs[scenes.GameSceneData](payload)
+		if err != nil {
+			return nil, err
+		}
+		// 로딩 화면을 거치지 않고 열면 여기서 바로 읽는다
+		if data.Assets == nil {
+			if data.Assets, err = scenes.LoadGameAssets(manager, data); err != nil {
+				return nil, err
+			}
+		}
+		return scenes.NewGameScene(display, manager, data)
+	})
+	registry.Register(scenes.StartSceneId, func(payload any) (scenes.Scene, error) {
+		return scenes.NewStartScene(manager), nil
+	})
+	registry.Register(scenes.PauseSceneId, func(payload any) (scenes.Scene, error) {
+		return scenes.NewPauseScene(), nil
+	})
+	registry.Register(scenes.LoadingSceneId, func(payload any) (scenes.Scene, error) {
+		data, err := scenes.PayloadAs[scenes.LoadingSceneData](payload)
+		if err != nil {
+			return nil, err
+		}
+		return scenes.NewLoadingScene(data)
+	})
+
+	sceneManager, err := scenes.NewManager(registry, scenes.StartSceneId, nil)
+	if err != nil {
+		return nil, err
 	}
-	activeSceneId := scenes.StartSceneId
-	sceneMap[activeSceneId].FirstLoad()
 
 	return &Game{
-		sceneMap,
-		activeSceneId,
-	}
+		sceneManager: sceneManager,
+		display:      display,
+		assets:       manager,
+	}, nil
 }
 
 func (g *Game) Update() error {
-	nextSceneId := g.sceneMap[g.activeSceneId].Update()
-	//switched scenes
-	if nextSceneId == scenes.ExitSceneId {
-		g.sceneMap[g.activeSceneId].OnExit()
-		return ebiten.Termination
-	}
-	if nextSceneId != g.activeSceneId {
-		nextScene := g.sceneMap[nextSceneId]
-		// if not loaded? then load in
-		if !nextScene.IsLoaded() {
-			nextScene.FirstLoad()
+	if g.hotReload {
+		g.ticks++
+		if g.ticks%reloadInterval == 0 {
+			if changed := g.assets.Poll(); len(changed) > 0 {
+				g.sceneManager.AssetsChanged(changed)
+			}
 		}
-		nextScene.OnEnter()
-		g.sceneMap[g.activeSceneId].OnExit()
 	}
-	g.activeSceneId = nextSceneId
-	return nil
+	return g.sceneManager.Update()
 }
 
 func (g *Game) Draw(screen *ebiten.Image) {
-	g.sceneMap[g.activeSceneId].Draw(screen)
+	g.sceneManager.Draw(screen)
 }
 
 func (g *Game) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int) {
-	return 320, 240
-	//return ebiten.WindowSize() // 창 크기 만큼 레이아웃 사이즈 조절
+	return g.display.Layout(outsideWidth, outsideHeight) // 설정된 스케일 모드에 따라 논리 해상도 결정
 }
+
+func (g *Game) DrawFinalScreen(screen ebiten.FinalScreen, offscreen *ebiten.Image, geoM ebiten.GeoM) {
+	g.display.DrawFinalScreen(screen, offscreen, geoM)
+}
+
+var _ ebiten.FinalScreenDrawer = (*Game)(nil)
@@ -0,0 +1,27 @@
+// Package imagefile decodes image files without creating ebiten images, so it
+// can run on a loading goroutine. Turn the result into an *ebiten.Image on the
+// main thread with ebiten.NewImageFromImage.
+package imagefile
+
+import (
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"io/fs"
+)
+
+// Decode reads and decodes the image called name in fsys.
+func Decode(fsys fs.FS, name string) (image.Image, error) {
+	file, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return img, nil
+}
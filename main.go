@@ -1,19 +1,34 @@
 package main
 
 import (
+	"flag"
 	"log"
 
+	"github.com/FunctionPointerXDD/Trader/assets"
+	"github.com/FunctionPointerXDD/Trader/display"
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
 func main() {
+	dev := flag.Bool("dev", false, "reload assets when their files change")
+	flag.Parse()
 
-	ebiten.SetWindowSize(640, 480) // 기본 창 사이즈
+	config, err := display.LoadConfig("config.json") // 파일이 없으면 기본 설정 사용
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ebiten.SetWindowSize(config.Width*config.WindowScale, config.Height*config.WindowScale) // 기본 창 사이즈
 	ebiten.SetWindowTitle("Hello, World!")
 	ebiten.SetWindowResizingMode((ebiten.WindowResizingModeEnabled)) // 전체 창 모드
 
-	var game *Game
-	game = NewGame()
+	manager := assets.NewManager(assets.Open("assets")) // 디스크에 assets 폴더가 없으면 바이너리에 포함된 에셋 사용
+	game, err := NewGame(display.NewDisplay(config), manager)
+	if err != nil {
+		log.Fatal(err)
+	}
+	game.hotReload = *dev
+
 	if err := ebiten.RunGame(game); err != nil { // Game이라는 구조체(이름은 상관없음) 하나를 정의해서 Update, Draw, Layout에 인터페이스 역할을 수행한다.
 		log.Fatal(err)
 	}
@@ -0,0 +1,176 @@
+package nav
+
+import (
+	"container/heap"
+	"image"
+	"math"
+)
+
+var neighbours = [8]image.Point{
+	{1, 0}, {-1, 0}, {0, 1}, {0, -1},
+	{1, 1}, {1, -1}, {-1, 1}, {-1, -1},
+}
+
+// Search is an A* search that can be spread over several ticks.
+type Search struct {
+	target      image.Point // the goal cell asked for
+	start, goal image.Point
+	startMoved  bool // start was blocked and moved to a walkable cell
+	open        openSet
+	cameFrom    map[image.Point]image.Point
+	cost        map[image.Point]float64
+	closed      map[image.Point]bool
+	expanded    int
+	done        bool
+	path        []image.Point
+}
+
+// newSearch prepares a search from start to goal. A blocked start or goal, like the
+// cell of an entity standing flush against a wall, is moved to a walkable cell
+// around it.
+func (g *Grid) newSearch(start, goal image.Point) *Search {
+	s := &Search{
+		target:   goal,
+		cameFrom: map[image.Point]image.Point{},
+		cost:     map[image.Point]float64{},
+		closed:   map[image.Point]bool{},
+	}
+	walkableStart, startOk := g.nearestWalkable(start, goal)
+	goal, goalOk := g.nearestWalkable(goal, start)
+	if !startOk || !goalOk {
+		s.done = true
+		return s
+	}
+	s.start, s.goal = walkableStart, goal
+	s.startMoved = walkableStart != start
+	start = walkableStart
+	s.cost[start] = 0
+	heap.Push(&s.open, openNode{start, octile(start, goal)})
+	return s
+}
+
+// Goal returns the goal cell the search was started for.
+func (s *Search) Goal() image.Point {
+	return s.target
+}
+
+// step expands at most limit more cells of s and returns how many it expanded. Once
+// s.done is set, s.path holds the cells of the path including both ends, or nil if
+// there is none.
+func (g *Grid) step(s *Search, limit int) (expanded int) {
+	for !s.done && s.open.Len() > 0 {
+		current := s.open[0].cell
+		if s.closed[current] {
+			heap.Pop(&s.open)
+			continue // 더 싼 경로로 이미 처리된 칸
+		}
+		if current == s.goal {
+			s.path = reconstruct(s.cameFrom, s.start, s.goal)
+			s.done = true
+			return expanded
+		}
+		if expanded >= limit {
+			return expanded
+		}
+		heap.Pop(&s.open)
+		s.closed[current] = true
+		s.expanded++
+		expanded++
+
+		for _, d := range neighbours {
+			next := current.Add(d)
+			if !g.Walkable(next) || s.closed[next] {
+				continue
+			}
+			// 대각선은 양 옆 칸이 모두 비어 있어야 한다 (모서리를 깎아 지나가지 않게)
+			if d.X != 0 && d.Y != 0 &&
+				(!g.Walkable(image.Pt(current.X+d.X, current.Y)) || !g.Walkable(image.Pt(current.X, current.Y+d.Y))) {
+				continue
+			}
+			step := 1.0
+			if d.X != 0 && d.Y != 0 {
+				step = math.Sqrt2
+			}
+			nextCost := s.cost[current] + step
+			if known, ok := s.cost[next]; ok && known <= nextCost {
+				continue
+			}
+			s.cost[next] = nextCost
+			s.cameFrom[next] = current
+			heap.Push(&s.open, openNode{next, nextCost + octile(next, s.goal)})
+		}
+	}
+	s.done = true
+	return expanded
+}
+
+// nearestWalkable returns cell if it is walkable, or else the walkable cell around
+// it that is closest to toward.
+func (g *Grid) nearestWalkable(cell, toward image.Point) (image.Point, bool) {
+	if g.Walkable(cell) {
+		return cell, true
+	}
+	best, found := cell, false
+	for _, d := range neighbours {
+		next := cell.Add(d)
+		if g.Walkable(next) && (!found || octile(next, toward) < octile(best, toward)) {
+			best, found = next, true
+		}
+	}
+	return best, found
+}
+
+// octile is the cost of the shortest 8-way path between two cells on an empty grid.
+func octile(a, b image.Point) float64 {
+	dx := math.Abs(float64(a.X - b.X))
+	dy := math.Abs(float64(a.Y - b.Y))
+	return math.Max(dx, dy) + (math.Sqrt2-1)*math.Min(dx, dy)
+}
+
+func reconstruct(cameFrom map[image.Point]image.Point, start, goal image.Point) []image.Point {
+	path := []image.Point{goal}
+	for cell := goal; cell != start; {
+		cell = cameFrom[cell]
+		path = append(path, cell)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// smooth drops the waypoints an entity can skip by walking straight to a later one.
+func (g *Grid) smooth(cells []image.Point) []image.Point {
+	if len(cells) <= 2 {
+		return cells
+	}
+	smoothed := []image.Point{cells[0]}
+	anchor := 0
+	for i := 2; i < len(cells); i++ {
+		ax, ay := g.CellCenter(cells[anchor])
+		bx, by := g.CellCenter(cells[i])
+		if !g.ClearLine(ax, ay, bx, by) {
+			anchor = i - 1
+			smoothed = append(smoothed, cells[anchor])
+		}
+	}
+	return append(smoothed, cells[len(cells)-1])
+}
+
+type openNode struct {
+	cell  image.Point
+	score float64
+}
+
+type openSet []openNode
+
+func (o openSet) Len() int           { return len(o) }
+func (o openSet) Less(i, j int) bool { return o[i].score < o[j].score }
+func (o openSet) Swap(i, j int)      { o[i], o[j] = o[j], o[i] }
+func (o *openSet) Push(x any)        { *o = append(*o, x.(openNode)) }
+func (o *openSet) Pop() any {
+	old := *o
+	node := old[len(old)-1]
+	*o = old[:len(old)-1]
+	return node
+}
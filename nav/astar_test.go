@@ -0,0 +1,153 @@
+package nav
+
+import (
+	"image"
+	"math"
+	"slices"
+	"testing"
+)
+
+// gridOf builds a grid from rows of text, # for blocked cells.
+func gridOf(rows ...string) *Grid {
+	colliders := make([]image.Rectangle, 0)
+	for y, row := range rows {
+		for x, c := range row {
+			if c == '#' {
+				colliders = append(colliders, rect(x, y, x+1, y+1))
+			}
+		}
+	}
+	return NewGrid(rect(0, 0, len(rows[0]), len(rows)), cell, colliders)
+}
+
+// searchAll runs a search to the end with no limit.
+func searchAll(g *Grid, start, goal image.Point) []image.Point {
+	s := g.newSearch(start, goal)
+	g.step(s, math.MaxInt)
+	return s.path
+}
+
+func TestSearch(t *testing.T) {
+	tests := []struct {
+		name        string
+		rows        []string
+		start, goal image.Point
+		wantLen     int // cells in the path including both ends, 0 for none
+	}{
+		{"straight", []string{"....."}, image.Pt(0, 0), image.Pt(4, 0), 5},
+		{"diagonal", []string{"...", "...", "..."}, image.Pt(0, 0), image.Pt(2, 2), 3},
+		{"same cell", []string{"..."}, image.Pt(1, 0), image.Pt(1, 0), 1},
+		{
+			"around a wall",
+			[]string{
+				".#...",
+				".#.#.",
+				"...#.",
+			},
+			image.Pt(0, 0), image.Pt(4, 0), 9,
+		},
+		{
+			// 대각선으로 벽 모서리를 깎아 지나가면 안 된다
+			"no corner cutting",
+			[]string{
+				".#",
+				"#.",
+			},
+			image.Pt(0, 0), image.Pt(1, 1), 0,
+		},
+		{
+			"unreachable",
+			[]string{
+				"..#..",
+				"..#..",
+			},
+			image.Pt(0, 0), image.Pt(4, 1), 0,
+		},
+		{"outside the grid", []string{"..."}, image.Pt(0, 0), image.Pt(9, 9), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gridOf(tt.rows...)
+			path := searchAll(g, tt.start, tt.goal)
+			if len(path) != tt.wantLen {
+				t.Fatalf("got path %v, want %d cells", path, tt.wantLen)
+			}
+			if len(path) == 0 {
+				return
+			}
+			if path[0] != tt.start || path[len(path)-1] != tt.goal {
+				t.Errorf("path %v doesn't run from %v to %v", path, tt.start, tt.goal)
+			}
+			for i, c := range path {
+				if !g.Walkable(c) {
+					t.Errorf("path goes through blocked cell %v", c)
+				}
+				if i > 0 {
+					if d := c.Sub(path[i-1]); max(abs(d.X), abs(d.Y)) != 1 {
+						t.Errorf("path jumps from %v to %v", path[i-1], c)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestSmooth(t *testing.T) {
+	g := gridOf(
+		"......",
+		"..##..",
+		"......",
+	)
+	tests := []struct {
+		name  string
+		cells []image.Point
+		want  []image.Point
+	}{
+		{
+			"straight run collapses",
+			[]image.Point{{0, 0}, {1, 0}, {2, 0}, {3, 0}, {4, 0}, {5, 0}},
+			[]image.Point{{0, 0}, {5, 0}},
+		},
+		{
+			"short paths are kept",
+			[]image.Point{{0, 0}, {1, 0}},
+			[]image.Point{{0, 0}, {1, 0}},
+		},
+		{
+			// 벽 아래로 돌아가는 길은 모퉁이를 남긴다
+			"keeps corners around walls",
+			[]image.Point{{1, 1}, {1, 2}, {2, 2}, {3, 2}, {4, 2}, {4, 1}},
+			[]image.Point{{1, 1}, {1, 2}, {4, 2}, {4, 1}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := g.smooth(tt.cells); !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOctile(t *testing.T) {
+	tests := []struct {
+		a, b image.Point
+		want float64
+	}{
+		{image.Pt(0, 0), image.Pt(3, 0), 3},
+		{image.Pt(0, 0), image.Pt(2, 2), 2 * math.Sqrt2},
+		{image.Pt(1, 1), image.Pt(-2, 3), 3 + 2*(math.Sqrt2-1)},
+	}
+	for _, tt := range tests {
+		if got := octile(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("octile(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
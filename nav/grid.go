@@ -0,0 +1,101 @@
+// Package nav finds walkable paths over the tile grid for computer-controlled entities.
+package nav
+
+import (
+	"image"
+	"math"
+)
+
+// Grid marks which cells of the map an entity one cell in size can stand in.
+type Grid struct {
+	bounds   image.Rectangle // in cells
+	cellSize int
+	blocked  []bool
+}
+
+// NewGrid builds a grid over pixelBounds with square cells of cellSize pixels. A cell
+// is blocked when any collider overlaps it.
+func NewGrid(pixelBounds image.Rectangle, cellSize int, colliders []image.Rectangle) *Grid {
+	bounds := image.Rect(
+		floorDiv(pixelBounds.Min.X, cellSize),
+		floorDiv(pixelBounds.Min.Y, cellSize),
+		floorDiv(pixelBounds.Max.X+cellSize-1, cellSize),
+		floorDiv(pixelBounds.Max.Y+cellSize-1, cellSize),
+	)
+	g := &Grid{
+		bounds:   bounds,
+		cellSize: cellSize,
+		blocked:  make([]bool, bounds.Dx()*bounds.Dy()),
+	}
+
+	for _, collider := range colliders {
+		cells := image.Rect(
+			floorDiv(collider.Min.X, cellSize),
+			floorDiv(collider.Min.Y, cellSize),
+			floorDiv(collider.Max.X+cellSize-1, cellSize),
+			floorDiv(collider.Max.Y+cellSize-1, cellSize),
+		).Intersect(bounds)
+		for y := cells.Min.Y; y < cells.Max.Y; y++ {
+			for x := cells.Min.X; x < cells.Max.X; x++ {
+				g.blocked[g.index(image.Pt(x, y))] = true
+			}
+		}
+	}
+	return g
+}
+
+func (g *Grid) CellSize() int {
+	return g.cellSize
+}
+
+// Walkable reports whether cell is inside the grid and not blocked.
+func (g *Grid) Walkable(cell image.Point) bool {
+	return cell.In(g.bounds) && !g.blocked[g.index(cell)]
+}
+
+// CellAt returns the cell containing the world pixel x, y.
+func (g *Grid) CellAt(x, y float64) image.Point {
+	size := float64(g.cellSize)
+	return image.Pt(int(math.Floor(x/size)), int(math.Floor(y/size)))
+}
+
+// CellCenter returns the world pixel at the middle of cell.
+func (g *Grid) CellCenter(cell image.Point) (float64, float64) {
+	size := float64(g.cellSize)
+	return (float64(cell.X) + 0.5) * size, (float64(cell.Y) + 0.5) * size
+}
+
+// ClearLine reports whether an entity one cell in size, centered on the segment,
+// can move from x0, y0 to x1, y1 without touching a blocked cell.
+func (g *Grid) ClearLine(x0, y0, x1, y1 float64) bool {
+	size := float64(g.cellSize)
+	half := size/2 - 0.5 // 칸에 딱 맞는 몸이 이웃 칸을 건드리지 않게 조금 줄인다
+	steps := int(math.Ceil(math.Hypot(x1-x0, y1-y0)/(size/4))) + 1
+
+	for i := 0; i <= steps; i++ {
+		t := float64(i) / float64(steps)
+		x, y := x0+(x1-x0)*t, y0+(y1-y0)*t
+		lo := g.CellAt(x-half, y-half)
+		hi := g.CellAt(x+half, y+half)
+		for cy := lo.Y; cy <= hi.Y; cy++ {
+			for cx := lo.X; cx <= hi.X; cx++ {
+				if !g.Walkable(image.Pt(cx, cy)) {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+func (g *Grid) index(cell image.Point) int {
+	return (cell.Y-g.bounds.Min.Y)*g.bounds.Dx() + cell.X - g.bounds.Min.X
+}
+
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
@@ -0,0 +1,76 @@
+package nav
+
+import "image"
+
+// maxSearchTicks caps a single search at this many ticks' worth of budget, so an
+// unreachable goal in a big map gives up instead of flooding the whole grid.
+const maxSearchTicks = 4
+
+// Planner hands out paths while keeping the A* work done per tick under a budget.
+// A search that doesn't fit in one tick carries on where it stopped the next.
+type Planner struct {
+	grid      *Grid
+	budget    int // cells expanded per tick
+	maxSearch int // cells expanded by one search, over all its ticks
+	used      int
+}
+
+func NewPlanner(grid *Grid, budget int) *Planner {
+	return &Planner{
+		grid:      grid,
+		budget:    budget,
+		maxSearch: budget * maxSearchTicks,
+	}
+}
+
+func (p *Planner) Grid() *Grid {
+	return p.grid
+}
+
+// BeginTick restores the budget. Call it once per tick before planning.
+func (p *Planner) BeginTick() {
+	p.used = 0
+}
+
+// Search starts planning a path from the cell containing fromX, fromY to the one
+// containing toX, toY. Pass it to FindPath until it is done.
+func (p *Planner) Search(fromX, fromY, toX, toY float64) *Search {
+	return p.grid.newSearch(p.grid.CellAt(fromX, fromY), p.grid.CellAt(toX, toY))
+}
+
+// FindPath spends what is left of the tick's budget on s. done is false when the
+// budget ran out first; call it again with s next tick. Once done, waypoints are
+// the smoothed path as world pixel cell centers. The start cell is left out unless
+// the entity has to step out of a blocked cell into it first. A goal that can't be
+// reached, or that takes more than the search cap to find, gives a nil path.
+func (p *Planner) FindPath(s *Search) (waypoints []image.Point, done bool) {
+	if !s.done {
+		remaining := min(p.budget-p.used, p.maxSearch-s.expanded)
+		if remaining <= 0 && s.expanded < p.maxSearch {
+			return nil, false
+		}
+		p.used += p.grid.step(s, remaining)
+		if !s.done {
+			if s.expanded < p.maxSearch {
+				return nil, false
+			}
+			// 최대치까지 찾아도 없으면 길이 없는 것으로 본다
+			s.done = true
+			s.path = nil
+		}
+	}
+	if s.path == nil {
+		return nil, true
+	}
+
+	cells := p.grid.smooth(s.path)
+	if !s.startMoved && len(cells) > 1 {
+		cells = cells[1:] // 이미 서 있는 칸
+	}
+	waypoints = make([]image.Point, 0, len(cells))
+	for _, cell := range cells {
+		x, y := p.grid.CellCenter(cell)
+		waypoints = append(waypoints, image.Pt(int(x), int(y)))
+	}
+	return waypoints, true
+}
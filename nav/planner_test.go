@@ -0,0 +1,128 @@
+package nav
+
+import (
+	"image"
+	"testing"
+)
+
+const cell = 16
+
+// rect returns the pixel rectangle covering cells x0, y0 to x1, y1 exclusive.
+func rect(x0, y0, x1, y1 int) image.Rectangle {
+	return image.Rect(x0*cell, y0*cell, x1*cell, y1*cell)
+}
+
+// center returns the pixel at the middle of cell x, y.
+func center(x, y int) (float64, float64) {
+	return (float64(x) + 0.5) * cell, (float64(y) + 0.5) * cell
+}
+
+// plan runs s tick by tick until it is done, and returns the path and the ticks it took.
+func plan(t *testing.T, p *Planner, s *Search, maxTicks int) ([]image.Point, int) {
+	t.Helper()
+	for tick := 1; tick <= maxTicks; tick++ {
+		p.BeginTick()
+		waypoints, done := p.FindPath(s)
+		if p.used > p.budget {
+			t.Fatalf("tick %d: used %d cells, budget is %d", tick, p.used, p.budget)
+		}
+		if done {
+			return waypoints, tick
+		}
+	}
+	t.Fatalf("search not done after %d ticks", maxTicks)
+	return nil, 0
+}
+
+func TestFindPathWalledIn(t *testing.T) {
+	// 목표 칸을 벽으로 완전히 둘러싼다
+	colliders := []image.Rectangle{
+		rect(79, 59, 82, 60),
+		rect(79, 62, 82, 63),
+		rect(79, 60, 80, 62),
+		rect(81, 60, 82, 62),
+	}
+	p := NewPlanner(NewGrid(rect(0, 0, 100, 80), cell, colliders), 2000)
+	fromX, fromY := center(1, 1)
+	toX, toY := center(80, 61)
+
+	waypoints, ticks := plan(t, p, p.Search(fromX, fromY, toX, toY), maxSearchTicks+1)
+	if waypoints != nil {
+		t.Errorf("got path %v to a walled-in goal, want none", waypoints)
+	}
+	if ticks > maxSearchTicks+1 {
+		t.Errorf("gave up after %d ticks", ticks)
+	}
+}
+
+func TestFindPathAcrossTicks(t *testing.T) {
+	// 긴 벽을 돌아가야 해서 한 틱의 예산으로는 모자라다
+	colliders := []image.Rectangle{rect(10, 0, 11, 29)}
+	p := NewPlanner(NewGrid(rect(0, 0, 20, 30), cell, colliders), 150)
+	fromX, fromY := center(5, 0)
+	toX, toY := center(15, 0)
+
+	waypoints, ticks := plan(t, p, p.Search(fromX, fromY, toX, toY), maxSearchTicks)
+	if ticks < 2 {
+		t.Errorf("found in %d tick, want the search spread over several", ticks)
+	}
+	if len(waypoints) == 0 {
+		t.Fatal("got no path around the wall")
+	}
+	if last, want := waypoints[len(waypoints)-1], image.Pt(int(toX), int(toY)); last != want {
+		t.Errorf("path ends at %v, want %v", last, want)
+	}
+}
+
+func TestFindPathBudgetShared(t *testing.T) {
+	p := NewPlanner(NewGrid(rect(0, 0, 50, 50), cell, []image.Rectangle{rect(25, 0, 26, 49)}), 30)
+	fromX, fromY := center(20, 0)
+	toX, toY := center(30, 0)
+	first, second := p.Search(fromX, fromY, toX, toY), p.Search(fromX, fromY, toX, toY)
+
+	p.BeginTick()
+	if _, done := p.FindPath(first); done {
+		t.Fatal("first search done within one tick's budget")
+	}
+	if _, done := p.FindPath(second); done {
+		t.Fatal("second search done with no budget left")
+	}
+	if second.expanded != 0 {
+		t.Errorf("second search expanded %d cells with no budget left", second.expanded)
+	}
+}
+
+func TestFindPathBlockedEnds(t *testing.T) {
+	tests := []struct {
+		name      string
+		from, to  image.Point
+		wantFirst image.Point // cell of the first waypoint
+		wantLast  image.Point
+	}{
+		{"blocked start", image.Pt(5, 5), image.Pt(9, 5), image.Pt(6, 5), image.Pt(9, 5)},
+		{"blocked goal", image.Pt(1, 5), image.Pt(5, 5), image.Pt(4, 5), image.Pt(4, 5)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 칸 경계에 맞지 않는 건물이 (5, 5) 칸을 막는다
+			colliders := []image.Rectangle{image.Rect(5*cell+8, 4*cell+8, 5*cell+12, 6*cell+8)}
+			grid := NewGrid(rect(0, 0, 12, 12), cell, colliders)
+			p := NewPlanner(grid, 1000)
+			fromX, fromY := center(tt.from.X, tt.from.Y)
+			toX, toY := center(tt.to.X, tt.to.Y)
+
+			waypoints, _ := plan(t, p, p.Search(fromX, fromY, toX, toY), 1)
+			if len(waypoints) == 0 {
+				t.Fatal("got no path")
+			}
+			first := grid.CellAt(float64(waypoints[0].X), float64(waypoints[0].Y))
+			last := waypoints[len(waypoints)-1]
+			if first != tt.wantFirst {
+				t.Errorf("first waypoint in cell %v, want %v", first, tt.wantFirst)
+			}
+			if got := grid.CellAt(float64(last.X), float64(last.Y)); got != tt.wantLast {
+				t.Errorf("last waypoint in cell %v, want %v", got, tt.wantLast)
+			}
+		})
+	}
+}
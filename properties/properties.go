@@ -0,0 +1,344 @@
+package properties
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+)
+
+type Type string
+
+// property types Tiled can store
+const (
+	String Type = "string"
+	Int    Type = "int"
+	Float  Type = "float"
+	Bool   Type = "bool"
+	Color  Type = "color"
+	File   Type = "file"
+	Object Type = "object"
+	Class  Type = "class"
+)
+
+var ErrNotFound = errors.New("property not found")
+
+// Property is a custom property set in Tiled. Value holds a string for String and File,
+// int for Int and Object (the referenced object's id), float64 for Float, bool for Bool,
+// color.NRGBA for Color and Properties for Class.
+type Property struct {
+	Name         string
+	Type         Type
+	PropertyType string // name of the custom class for Class properties
+	Value        any
+}
+
+// Properties is the property bag of a map, layer, tile or object, keyed by property name.
+type Properties map[string]*Property
+
+func (p Properties) get(name string, t Type) (*Property, error) {
+	property, ok := p[name]
+	if !ok {
+		return nil, fmt.Errorf("%q: %w", name, ErrNotFound)
+	}
+	if property.Type != t {
+		return nil, fmt.Errorf("property %q is %s, not %s", name, property.Type, t)
+	}
+	return property, nil
+}
+
+func (p Properties) Has(name string) bool {
+	_, ok := p[name]
+	return ok
+}
+
+func (p Properties) String(name string) (string, error) {
+	property, err := p.get(name, String)
+	if err != nil {
+		return "", err
+	}
+	return property.Value.(string), nil
+}
+
+func (p Properties) Int(name string) (int, error) {
+	property, err := p.get(name, Int)
+	if err != nil {
+		return 0, err
+	}
+	return property.Value.(int), nil
+}
+
+// Float also accepts Int properties, since Tiled can't tell 1.0 from 1 in class members.
+func (p Properties) Float(name string) (float64, error) {
+	if property, ok := p[name]; ok && property.Type == Int {
+		return float64(property.Value.(int)), nil
+	}
+	property, err := p.get(name, Float)
+	if err != nil {
+		return 0, err
+	}
+	return property.Value.(float64), nil
+}
+
+func (p Properties) Bool(name string) (bool, error) {
+	property, err := p.get(name, Bool)
+	if err != nil {
+		return false, err
+	}
+	return property.Value.(bool), nil
+}
+
+func (p Properties) Color(name string) (color.NRGBA, error) {
+	property, err := p.get(name, Color)
+	if err != nil {
+		return color.NRGBA{}, err
+	}
+	return property.Value.(color.NRGBA), nil
+}
+
+// File returns the path of a File property, relative to the file that defines it.
+func (p Properties) File(name string) (string, error) {
+	property, err := p.get(name, File)
+	if err != nil {
+		return "", err
+	}
+	return property.Value.(string), nil
+}
+
+// Object returns the id of the object an Object property refers to, 0 when unset.
+func (p Properties) Object(name string) (int, error) {
+	property, err := p.get(name, Object)
+	if err != nil {
+		return 0, err
+	}
+	return property.Value.(int), nil
+}
+
+// Class returns the members of a Class property. Members left at their default in
+// Tiled aren't saved, so they are missing from the result.
+func (p Properties) Class(name string) (Properties, error) {
+	property, err := p.get(name, Class)
+	if err != nil {
+		return nil, err
+	}
+	return property.Value.(Properties), nil
+}
+
+// UnmarshalJSON reads Tiled's JSON "properties" array.
+func (p *Properties) UnmarshalJSON(contents []byte) error {
+	var raw []struct {
+		Name         string          `json:"name"`
+		Type         Type            `json:"type"`
+		PropertyType string          `json:"propertytype"`
+		Value        json.RawMessage `json:"value"`
+	}
+	if err := json.Unmarshal(contents, &raw); err != nil {
+		return err
+	}
+
+	*p = make(Properties, len(raw))
+	for _, rawProperty := range raw {
+		property := Property{
+			Name:         rawProperty.Name,
+			Type:         rawProperty.Type,
+			PropertyType: rawProperty.PropertyType,
+		}
+		if property.Type == "" {
+			property.Type = String
+		}
+
+		var value any
+		if err := json.Unmarshal(rawProperty.Value, &value); err != nil {
+			return fmt.Errorf("property %q: %w", property.Name, err)
+		}
+		if err := property.setJSON(value); err != nil {
+			return fmt.Errorf("property %q: %w", property.Name, err)
+		}
+		(*p)[property.Name] = &property
+	}
+
+	return nil
+}
+
+// setJSON converts a value decoded by encoding/json into the Go type of the property's Type.
+func (p *Property) setJSON(value any) error {
+	mismatch := fmt.Errorf("value %v doesn't match type %s", value, p.Type)
+
+	switch p.Type {
+	case String, File:
+		s, ok := value.(string)
+		if !ok {
+			return mismatch
+		}
+		p.Value = s
+
+	case Color:
+		s, ok := value.(string)
+		if !ok {
+			return mismatch
+		}
+		c, err := parseColor(s)
+		if err != nil {
+			return err
+		}
+		p.Value = c
+
+	case Int, Object:
+		f, ok := value.(float64)
+		if !ok || f != math.Trunc(f) {
+			return mismatch
+		}
+		p.Value = int(f)
+
+	case Float:
+		f, ok := value.(float64)
+		if !ok {
+			return mismatch
+		}
+		p.Value = f
+
+	case Bool:
+		b, ok := value.(bool)
+		if !ok {
+			return mismatch
+		}
+		p.Value = b
+
+	case Class:
+		members, ok := value.(map[string]any)
+		if !ok {
+			return mismatch
+		}
+		p.Value = classMembers(members)
+
+	default:
+		return fmt.Errorf("unknown property type %q", p.Type)
+	}
+
+	return nil
+}
+
+// classMembers converts the members of a JSON class value. Tiled only saves their values,
+// so the member types are inferred from the JSON types.
+func classMembers(members map[string]any) Properties {
+	properties := make(Properties, len(members))
+	for name, value := range members {
+		property := Property{
+			Name:  name,
+			Value: value,
+		}
+		switch v := value.(type) {
+		case bool:
+			property.Type = Bool
+		case float64:
+			property.Type = Float
+			if v == math.Trunc(v) {
+				property.Type = Int
+				property.Value = int(v)
+			}
+		case map[string]any:
+			property.Type = Class
+			property.Value = classMembers(v)
+		default:
+			property.Type = String
+			property.Value = fmt.Sprint(v)
+		}
+		properties[name] = &property
+	}
+	return properties
+}
+
+type xmlProperty struct {
+	Name         string        `xml:"name,attr"`
+	Type         Type          `xml:"type,attr"`
+	PropertyType string        `xml:"propertytype,attr"`
+	Value        *string       `xml:"value,attr"`
+	Text         string        `xml:",chardata"` // multi-line strings are stored as text instead of value
+	Members      []xmlProperty `xml:"properties>property"`
+}
+
+// UnmarshalXML reads a TMX/TSX <properties> element.
+func (p *Properties) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var raw struct {
+		Properties []xmlProperty `xml:"property"`
+	}
+	if err := d.DecodeElement(&raw, &start); err != nil {
+		return err
+	}
+
+	properties, err := fromXML(raw.Properties)
+	if err != nil {
+		return err
+	}
+	*p = properties
+
+	return nil
+}
+
+func fromXML(raw []xmlProperty) (Properties, error) {
+	properties := make(Properties, len(raw))
+	for _, rawProperty := range raw {
+		property := Property{
+			Name:         rawProperty.Name,
+			Type:         rawProperty.Type,
+			PropertyType: rawProperty.PropertyType,
+		}
+		if property.Type == "" {
+			property.Type = String
+		}
+
+		value := rawProperty.Text
+		if rawProperty.Value != nil {
+			value = *rawProperty.Value
+		}
+
+		var err error
+		switch property.Type {
+		case String, File:
+			property.Value = value
+		case Color:
+			property.Value, err = parseColor(value)
+		case Int, Object:
+			property.Value, err = strconv.Atoi(value)
+		case Float:
+			property.Value, err = strconv.ParseFloat(value, 64)
+		case Bool:
+			property.Value, err = strconv.ParseBool(value)
+		case Class:
+			property.Value, err = fromXML(rawProperty.Members)
+		default:
+			err = fmt.Errorf("unknown property type %q", property.Type)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("property %q: %w", property.Name, err)
+		}
+
+		properties[property.Name] = &property
+	}
+
+	return properties, nil
+}
+
+// parseColor parses Tiled's "#AARRGGBB" or "#RRGGBB" colors. An empty color is transparent.
+func parseColor(s string) (color.NRGBA, error) {
+	hex := strings.TrimPrefix(s, "#")
+	if hex == "" {
+		return color.NRGBA{}, nil
+	}
+
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.NRGBA{}, fmt.Errorf("invalid color %q", s)
+	}
+	switch len(hex) {
+	case 6:
+		return color.NRGBA{uint8(v >> 16), uint8(v >> 8), uint8(v), 0xff}, nil
+	case 8:
+		return color.NRGBA{uint8(v >> 16), uint8(v >> 8), uint8(v), uint8(v >> 24)}, nil
+	}
+	return color.NRGBA{}, fmt.Errorf("invalid color %q", s)
+}
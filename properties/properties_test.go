@@ -0,0 +1,194 @@
+package properties
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"image/color"
+	"testing"
+)
+
+func TestUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		want    any
+		wantErr bool
+	}{
+		{"string", `{"name":"p","type":"string","value":"hi"}`, "hi", false},
+		{"untyped is string", `{"name":"p","value":"hi"}`, "hi", false},
+		{"int", `{"name":"p","type":"int","value":3}`, 3, false},
+		{"float", `{"name":"p","type":"float","value":1.5}`, 1.5, false},
+		{"bool", `{"name":"p","type":"bool","value":true}`, true, false},
+		{"color", `{"name":"p","type":"color","value":"#80ff0000"}`, color.NRGBA{0xff, 0, 0, 0x80}, false},
+		{"file", `{"name":"p","type":"file","value":"../a.png"}`, "../a.png", false},
+		{"object", `{"name":"p","type":"object","value":12}`, 12, false},
+		{"fractional int", `{"name":"p","type":"int","value":1.5}`, nil, true},
+		{"int as string", `{"name":"p","type":"int","value":"3"}`, nil, true},
+		{"bool as number", `{"name":"p","type":"bool","value":1}`, nil, true},
+		{"bad color", `{"name":"p","type":"color","value":"#12"}`, nil, true},
+		{"unknown type", `{"name":"p","type":"vector","value":1}`, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p Properties
+			err := json.Unmarshal([]byte("["+tt.json+"]"), &p)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, want error %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := p["p"].Value; got != tt.want {
+				t.Errorf("value = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnmarshalJSONClass(t *testing.T) {
+	var p Properties
+	err := json.Unmarshal([]byte(`[{"name":"stats","type":"class","propertytype":"Stats",
+		"value":{"health":3,"speed":0.5,"boss":true,"title":"king","drop":{"heal":1}}}]`), &p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p["stats"].PropertyType != "Stats" {
+		t.Errorf("property type = %q, want Stats", p["stats"].PropertyType)
+	}
+	stats, err := p.Class("stats")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if health, err := stats.Int("health"); err != nil || health != 3 {
+		t.Errorf("health = %v, %v, want 3", health, err)
+	}
+	if speed, err := stats.Float("speed"); err != nil || speed != 0.5 {
+		t.Errorf("speed = %v, %v, want 0.5", speed, err)
+	}
+	if boss, err := stats.Bool("boss"); err != nil || !boss {
+		t.Errorf("boss = %v, %v, want true", boss, err)
+	}
+	if title, err := stats.String("title"); err != nil || title != "king" {
+		t.Errorf("title = %q, %v, want king", title, err)
+	}
+	drop, err := stats.Class("drop")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if heal, err := drop.Int("heal"); err != nil || heal != 1 {
+		t.Errorf("drop.heal = %v, %v, want 1", heal, err)
+	}
+}
+
+func TestUnmarshalXML(t *testing.T) {
+	var raw struct {
+		Properties Properties `xml:"properties"`
+	}
+	err := xml.Unmarshal([]byte(`<object>
+ <properties>
+  <property name="name" value="bob"/>
+  <property name="health" type="int" value="4"/>
+  <property name="speed" type="float" value="1.25"/>
+  <property name="boss" type="bool" value="true"/>
+  <property name="tint" type="color" value="#00ff00"/>
+  <property name="note">line one
+line two</property>
+  <property name="stats" type="class" propertytype="Stats">
+   <properties>
+    <property name="armor" type="int" value="2"/>
+   </properties>
+  </property>
+ </properties>
+</object>`), &raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := raw.Properties
+
+	want := map[string]any{
+		"name":   "bob",
+		"health": 4,
+		"speed":  1.25,
+		"boss":   true,
+		"tint":   color.NRGBA{0, 0xff, 0, 0xff},
+		"note":   "line one\nline two",
+	}
+	for name, value := range want {
+		if got := p[name].Value; got != value {
+			t.Errorf("%s = %#v, want %#v", name, got, value)
+		}
+	}
+	stats, err := p.Class("stats")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if armor, err := stats.Int("armor"); err != nil || armor != 2 {
+		t.Errorf("stats.armor = %v, %v, want 2", armor, err)
+	}
+
+	for _, bad := range []string{
+		`<property name="p" type="int" value="x"/>`,
+		`<property name="p" type="bool" value="maybe"/>`,
+		`<property name="p" type="vector" value="1"/>`,
+	} {
+		var raw struct {
+			Properties Properties `xml:"properties"`
+		}
+		if err := xml.Unmarshal([]byte("<o><properties>"+bad+"</properties></o>"), &raw); err == nil {
+			t.Errorf("%s: got no error", bad)
+		}
+	}
+}
+
+func TestGetters(t *testing.T) {
+	p := Properties{
+		"count": {Name: "count", Type: Int, Value: 2},
+		"label": {Name: "label", Type: String, Value: "x"},
+		"ratio": {Name: "ratio", Type: Float, Value: 0.25},
+	}
+
+	if _, err := p.Int("missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("missing: err = %v, want ErrNotFound", err)
+	}
+	if _, err := p.Int("label"); err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("Int of a string: err = %v, want a type error", err)
+	}
+	if _, err := p.Bool("count"); err == nil {
+		t.Error("Bool of an int: got no error")
+	}
+	if f, err := p.Float("count"); err != nil || f != 2 {
+		t.Errorf("Float of an int = %v, %v, want 2", f, err)
+	}
+	if f, err := p.Float("ratio"); err != nil || f != 0.25 {
+		t.Errorf("Float = %v, %v, want 0.25", f, err)
+	}
+	if !p.Has("label") || p.Has("missing") {
+		t.Error("Has reports the wrong properties")
+	}
+}
+
+func TestParseColor(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    color.NRGBA
+		wantErr bool
+	}{
+		{"#ff8000", color.NRGBA{0xff, 0x80, 0x00, 0xff}, false},
+		{"#40ff8000", color.NRGBA{0xff, 0x80, 0x00, 0x40}, false},
+		{"ff8000", color.NRGBA{0xff, 0x80, 0x00, 0xff}, false},
+		{"", color.NRGBA{}, false},
+		{"#fff", color.NRGBA{}, true},
+		{"#gg0000", color.NRGBA{}, true},
+	}
+	for _, tt := range tests {
+		got, err := parseColor(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseColor(%q): err = %v, want error %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseColor(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,320 @@
+package render
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/FunctionPointerXDD/Trader/camera"
+	"github.com/FunctionPointerXDD/Trader/tilemap"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// ChunkSize is the width and height of a pre-rendered chunk, in tiles.
+const ChunkSize = 16
+
+// dynamicTile is a tile that can't be baked into a chunk image and is drawn every frame:
+// either animated, or tall enough that sprites can stand behind it.
+type dynamicTile struct {
+	gid  uint32
+	cell image.Point // tile coordinates
+	tall bool
+}
+
+type chunk struct {
+	img     *ebiten.Image // nil when nothing static was drawn into the chunk
+	dynamic []dynamicTile
+	dirty   bool
+}
+
+// MapRenderer draws the tile layers of a map from chunk images baked at load time,
+// only touching the chunks visible through the camera. Tiles taller than a map cell,
+// like buildings, are left out of the layers and queued with the sprites instead.
+type MapRenderer struct {
+	tilemap  *tilemap.Tilemap
+	tilesets *tilemap.Tilesets
+	chunks   []map[image.Point]*chunk // per layer, keyed by chunk coordinates
+	overflow image.Point              // how far tile images can reach past their cell, in pixels
+}
+
+func NewMapRenderer(tm *tilemap.Tilemap, tilesets *tilemap.Tilesets) (*MapRenderer, error) {
+	m := &MapRenderer{
+		tilemap:  tm,
+		tilesets: tilesets,
+		chunks:   make([]map[image.Point]*chunk, len(tm.Layers)),
+	}
+
+	for layerIndex, layer := range tm.Layers {
+		m.chunks[layerIndex] = make(map[image.Point]*chunk)
+		for _, gid := range layer.Data {
+			if gid == 0 {
+				continue
+			}
+			tile, err := tilesets.Tile(gid)
+			if err != nil {
+				return nil, err
+			}
+			w, h := tile.Size()
+			m.overflow.X = max(m.overflow.X, w-tm.TileWidth)
+			m.overflow.Y = max(m.overflow.Y, h-tm.TileHeight)
+		}
+	}
+
+	for layerIndex, layer := range tm.Layers {
+		if layer.Type != tilemap.TileLayer {
+			continue
+		}
+		bounds := layer.Bounds()
+		for y := floorDiv(bounds.Min.Y, ChunkSize); y*ChunkSize < bounds.Max.Y; y++ {
+			for x := floorDiv(bounds.Min.X, ChunkSize); x*ChunkSize < bounds.Max.X; x++ {
+				point := image.Pt(x, y)
+				c := &chunk{}
+				m.bake(layerIndex, point, c)
+				m.chunks[layerIndex][point] = c
+			}
+		}
+	}
+
+	return m, nil
+}
+
+// Dispose frees the chunk images. The renderer must not be drawn afterwards.
+func (m *MapRenderer) Dispose() {
+	for _, chunks := range m.chunks {
+		for _, c := range chunks {
+			if c.img != nil {
+				c.img.Deallocate()
+				c.img = nil
+			}
+		}
+	}
+}
+
+// SetTile changes a tile at runtime and invalidates the chunks it is drawn into.
+func (m *MapRenderer) SetTile(layerIndex, x, y int, gid uint32) error {
+	if layerIndex < 0 || layerIndex >= len(m.tilemap.Layers) {
+		return fmt.Errorf("no layer %d", layerIndex)
+	}
+	layer := &m.tilemap.Layers[layerIndex]
+	if !image.Pt(x, y).In(layer.Bounds()) {
+		return fmt.Errorf("tile %d,%d is outside layer %q", x, y, layer.Name)
+	}
+	if gid != 0 {
+		if _, err := m.tilesets.Tile(gid); err != nil {
+			return err
+		}
+	}
+
+	index := (y-layer.StartY)*layer.Width + x - layer.StartX
+	old := layer.Data[index]
+	layer.Data[index] = gid
+
+	// both the old and the new image may reach into neighbouring chunks
+	reach := m.tileRect(image.Pt(x, y), old).Union(m.tileRect(image.Pt(x, y), gid))
+	for point, c := range m.chunks[layerIndex] {
+		if m.chunkRect(point).Overlaps(reach) {
+			c.dirty = true
+		}
+	}
+
+	return nil
+}
+
+// Draw draws the tile layers, animated tiles showing their frame elapsedMs into the map.
+func (m *MapRenderer) Draw(screen *ebiten.Image, cam *camera.Camera, elapsedMs int) {
+	view, reach := m.view(cam)
+
+	camGeoM := cam.GeoM()
+	opts := ebiten.DrawImageOptions{}
+
+	for layerIndex, chunks := range m.chunks {
+		visible := make([]*chunk, 0)
+		for point, c := range chunks {
+			rect := m.chunkRect(point)
+			if !rect.Overlaps(reach) {
+				continue
+			}
+			if c.dirty {
+				m.bake(layerIndex, point, c)
+			}
+			visible = append(visible, c)
+
+			if c.img != nil && rect.Overlaps(view) {
+				opts.GeoM.Translate(float64(rect.Min.X), float64(rect.Min.Y))
+				opts.GeoM.Concat(camGeoM)
+				screen.DrawImage(c.img, &opts)
+				opts.GeoM.Reset()
+			}
+		}
+
+		// animated tiles are drawn after all of the layer's baked chunks
+		for _, c := range visible {
+			for _, dynamic := range c.dynamic {
+				if dynamic.tall {
+					continue
+				}
+				tile, err := m.tilesets.AnimatedTile(dynamic.gid, elapsedMs)
+				if err != nil {
+					continue
+				}
+				opts.GeoM = m.tileGeoM(tile, dynamic.cell)
+				opts.GeoM.Concat(camGeoM)
+				screen.DrawImage(tile.Img, &opts)
+				opts.GeoM.Reset()
+			}
+		}
+	}
+}
+
+// QueueTallTiles pushes the visible tall tiles of every layer into queue, standing
+// on the bottom edge of their cell.
+func (m *MapRenderer) QueueTallTiles(queue *Queue, cam *camera.Camera, elapsedMs int) {
+	_, reach := m.view(cam)
+
+	for layerIndex, chunks := range m.chunks {
+		for point, c := range chunks {
+			if !m.chunkRect(point).Overlaps(reach) {
+				continue
+			}
+			if c.dirty {
+				m.bake(layerIndex, point, c)
+			}
+			for _, dynamic := range c.dynamic {
+				if !dynamic.tall {
+					continue
+				}
+				tile, err := m.tilesets.AnimatedTile(dynamic.gid, elapsedMs)
+				if err != nil {
+					continue
+				}
+				queue.Push(tile.Img, m.tileGeoM(tile, dynamic.cell), float64((dynamic.cell.Y+1)*m.tilemap.TileHeight))
+			}
+		}
+	}
+}
+
+// view returns the map pixels visible through the camera, and the area whose chunks can reach
+// into them: dynamic tiles belong to the chunk of their cell but reach right and up past it.
+func (m *MapRenderer) view(cam *camera.Camera) (image.Rectangle, image.Rectangle) {
+	view := cam.ViewRect()
+	reach := image.Rect(view.Min.X-m.overflow.X, view.Min.Y, view.Max.X, view.Max.Y+m.overflow.Y)
+	return view, reach
+}
+
+// bake redraws a chunk's image from every static tile whose image reaches into it.
+func (m *MapRenderer) bake(layerIndex int, point image.Point, c *chunk) {
+	layer := &m.tilemap.Layers[layerIndex]
+	rect := m.chunkRect(point)
+	cells := image.Rect(
+		point.X*ChunkSize,
+		point.Y*ChunkSize,
+		(point.X+1)*ChunkSize,
+		(point.Y+1)*ChunkSize,
+	)
+	// tiles reach right and up from their cell
+	cells.Min.X -= ceilDiv(m.overflow.X, m.tilemap.TileWidth)
+	cells.Max.Y += ceilDiv(m.overflow.Y, m.tilemap.TileHeight)
+	cells = cells.Intersect(layer.Bounds())
+
+	if c.img != nil {
+		c.img.Clear()
+	}
+	c.dynamic = c.dynamic[:0]
+	drawn := false
+
+	opts := ebiten.DrawImageOptions{}
+	for y := cells.Min.Y; y < cells.Max.Y; y++ {
+		for x := cells.Min.X; x < cells.Max.X; x++ {
+			gid := layer.Data[(y-layer.StartY)*layer.Width+x-layer.StartX]
+			if gid == 0 {
+				continue
+			}
+			tile, err := m.tilesets.Tile(gid)
+			if err != nil {
+				continue
+			}
+			cell := image.Pt(x, y)
+
+			_, h := tile.Size()
+			tall := h > m.tilemap.TileHeight
+			if tall || tile.Tileset.Animated(tile.Id) {
+				if cell.In(m.chunkCells(point)) {
+					c.dynamic = append(c.dynamic, dynamicTile{gid, cell, tall})
+				}
+				continue
+			}
+			if !m.tileRect(cell, gid).Overlaps(rect) {
+				continue
+			}
+
+			if c.img == nil {
+				c.img = ebiten.NewImage(rect.Dx(), rect.Dy())
+			}
+			opts.GeoM = m.tileGeoM(tile, cell)
+			opts.GeoM.Translate(float64(-rect.Min.X), float64(-rect.Min.Y))
+			c.img.DrawImage(tile.Img, &opts)
+			opts.GeoM.Reset()
+			drawn = true
+		}
+	}
+
+	if !drawn && c.img != nil {
+		c.img.Deallocate()
+		c.img = nil
+	}
+	c.dirty = false
+}
+
+// tileGeoM places a tile's image in map pixels. Tiled anchors tiles at the bottom-left of
+// their cell, so taller images grow upward.
+func (m *MapRenderer) tileGeoM(tile *tilemap.Tile, cell image.Point) ebiten.GeoM {
+	_, h := tile.Size()
+	geoM := tile.GeoM()
+	geoM.Translate(
+		float64(cell.X*m.tilemap.TileWidth),
+		float64((cell.Y+1)*m.tilemap.TileHeight-h),
+	)
+	return geoM
+}
+
+// tileRect returns the pixels covered by the image of gid drawn at cell.
+func (m *MapRenderer) tileRect(cell image.Point, gid uint32) image.Rectangle {
+	if gid == 0 {
+		return image.Rectangle{}
+	}
+	tile, err := m.tilesets.Tile(gid)
+	if err != nil {
+		return image.Rectangle{}
+	}
+	w, h := tile.Size()
+	x := cell.X * m.tilemap.TileWidth
+	y := (cell.Y + 1) * m.tilemap.TileHeight
+	return image.Rect(x, y-h, x+w, y)
+}
+
+// chunkRect returns the pixels covered by a chunk.
+func (m *MapRenderer) chunkRect(point image.Point) image.Rectangle {
+	cells := m.chunkCells(point)
+	return image.Rect(
+		cells.Min.X*m.tilemap.TileWidth,
+		cells.Min.Y*m.tilemap.TileHeight,
+		cells.Max.X*m.tilemap.TileWidth,
+		cells.Max.Y*m.tilemap.TileHeight,
+	)
+}
+
+// chunkCells returns the tiles of a chunk.
+func (m *MapRenderer) chunkCells(point image.Point) image.Rectangle {
+	return image.Rect(point.X*ChunkSize, point.Y*ChunkSize, (point.X+1)*ChunkSize, (point.Y+1)*ChunkSize)
+}
+
+// floorDiv divides rounding toward negative infinity, for chunks of infinite maps left of or above 0,0.
+func floorDiv(a, b int) int {
+	if a < 0 {
+		return -((-a + b - 1) / b)
+	}
+	return a / b
+}
+
+func ceilDiv(a, b int) int {
+	return (a + b - 1) / b
+}
@@ -0,0 +1,49 @@
+package render
+
+import (
+	"sort"
+
+	"github.com/FunctionPointerXDD/Trader/camera"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+type item struct {
+	img   *ebiten.Image
+	geoM  ebiten.GeoM
+	footY float64
+}
+
+// Queue collects sprites and tall tiles for one frame and draws them sorted by the
+// y of their feet, so things further down the screen are drawn in front.
+type Queue struct {
+	items []item
+}
+
+func NewQueue() *Queue {
+	return &Queue{
+		items: make([]item, 0),
+	}
+}
+
+// Push queues img placed in map pixels by geoM, standing on the line y = footY.
+func (q *Queue) Push(img *ebiten.Image, geoM ebiten.GeoM, footY float64) {
+	q.items = append(q.items, item{img, geoM, footY})
+}
+
+// Draw draws the queued images back to front and empties the queue.
+// Images with the same foot y keep the order they were pushed in.
+func (q *Queue) Draw(screen *ebiten.Image, cam *camera.Camera) {
+	sort.SliceStable(q.items, func(i, j int) bool {
+		return q.items[i].footY < q.items[j].footY
+	})
+
+	camGeoM := cam.GeoM()
+	opts := ebiten.DrawImageOptions{}
+	for _, item := range q.items {
+		opts.GeoM = item.geoM
+		opts.GeoM.Concat(camGeoM)
+		screen.DrawImage(item.img, &opts)
+	}
+
+	q.items = q.items[:0]
+}
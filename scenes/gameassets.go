@@ -0,0 +1,162 @@
+package scenes
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/FunctionPointerXDD/Trader/assets"
+	"github.com/FunctionPointerXDD/Trader/entities"
+	"github.com/FunctionPointerXDD/Trader/render"
+	"github.com/FunctionPointerXDD/Trader/spawner"
+	"github.com/FunctionPointerXDD/Trader/tilemap"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// GameAssets is everything a GameScene needs, loaded ahead of time by a LoadingScene.
+type GameAssets struct {
+	PlayerImg   *ebiten.Image
+	SkeletonImg *ebiten.Image
+	PotionImg   *ebiten.Image
+	TiledMap    *tilemap.Tilemap
+	Tilesets    *tilemap.Tilesets
+	MapRenderer *render.MapRenderer
+	Colliders   []image.Rectangle
+	World       *entities.World // the map's objects, without the player
+	Spawned     *spawner.Spawned
+}
+
+// GameAssetsJob loads the assets for a GameScene and opens it.
+type GameAssetsJob struct {
+	manager *assets.Manager
+	data    GameSceneData
+
+	tiledMap *tilemap.Tilemap // filled in by the worker
+}
+
+func NewGameAssetsJob(manager *assets.Manager, data GameSceneData) *GameAssetsJob {
+	if data.Map == "" {
+		data.Map = DefaultMap
+	}
+	return &GameAssetsJob{
+		manager: manager,
+		data:    data,
+	}
+}
+
+// Steps implements [LoadJob].
+func (j *GameAssetsJob) Steps() []LoadStep {
+	// 워커에서는 디코딩만 해서 캐시에 넣어두고, ebiten 이미지는 build에서 만든다
+	decode := func(name string) func() error {
+		return func() error {
+			_, err := j.manager.DecodeImage(name)
+			return err
+		}
+	}
+	return []LoadStep{
+		{"player", decode(assets.PlayerImage)},
+		{"enemies", decode(assets.SkeletonImage)},
+		{"items", decode(assets.PotionImage)},
+		{"map", func() (err error) {
+			j.tiledMap, err = j.manager.Tilemap(j.data.Map)
+			return j.forgetMapOn(err)
+		}},
+		{"tilesets", func() (err error) {
+			_, err = j.manager.DecodeTilesets(j.data.Map)
+			return j.forgetMapOn(err)
+		}},
+	}
+}
+
+// Finish implements [LoadJob].
+func (j *GameAssetsJob) Finish() (Next, error) {
+	assets, err := j.build()
+	if err != nil {
+		return StayHere(), j.forgetMapOn(err)
+	}
+	data := j.data
+	data.Assets = assets
+	return SwitchTo(GameSceneId, Fade(30)).WithPayload(data), nil
+}
+
+// forgetMapOn drops the cached map when err is set, so a retry reads the fixed
+// files instead of failing on the cached ones again. It returns err.
+func (j *GameAssetsJob) forgetMapOn(err error) error {
+	if err != nil {
+		j.manager.Forget(j.data.Map)
+	}
+	return err
+}
+
+// build creates the ebiten images and everything drawn from them. It runs on the main thread.
+func (j *GameAssetsJob) build() (*GameAssets, error) {
+	tilesets, err := j.manager.Tilesets(j.data.Map)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := j.tiledMap.Validate(tilesets); err != nil {
+		return nil, fmt.Errorf("%s: %w", j.data.Map, err)
+	}
+
+	mapRenderer, err := render.NewMapRenderer(j.tiledMap, tilesets)
+	if err != nil {
+		return nil, err
+	}
+
+	colliders, err := j.tiledMap.Colliders(tilesets)
+	if err != nil {
+		return nil, err
+	}
+
+	playerImg, err := j.manager.Image(assets.PlayerImage)
+	if err != nil {
+		return nil, err
+	}
+	skeletonImg, err := j.manager.Image(assets.SkeletonImage)
+	if err != nil {
+		return nil, err
+	}
+	potionImg, err := j.manager.Image(assets.PotionImage)
+	if err != nil {
+		return nil, err
+	}
+
+	world := entities.NewWorld()
+	spawned, err := spawner.NewSpawner(skeletonImg, potionImg).Spawn(world, j.tiledMap.Objects())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", j.data.Map, err)
+	}
+	if _, ok := spawned.SpawnPoint(j.data.SpawnPoint); !ok && j.data.SpawnPoint != "" {
+		return nil, fmt.Errorf("%s: no %s named %q", j.data.Map, spawner.ClassPlayerStart, j.data.SpawnPoint)
+	}
+
+	return &GameAssets{
+		PlayerImg:   playerImg,
+		SkeletonImg: skeletonImg,
+		PotionImg:   potionImg,
+		TiledMap:    j.tiledMap,
+		Tilesets:    tilesets,
+		MapRenderer: mapRenderer,
+		Colliders:   colliders,
+		World:       world,
+		Spawned:     spawned,
+	}, nil
+}
+
+// LoadGameAssets runs the job's steps and build on the calling goroutine, for
+// opening a GameScene without a LoadingScene in front of it.
+func LoadGameAssets(manager *assets.Manager, data GameSceneData) (*GameAssets, error) {
+	job := NewGameAssetsJob(manager, data)
+	for _, step := range job.Steps() {
+		if err := step.Run(); err != nil {
+			return nil, fmt.Errorf("%s: %w", step.Name, err)
+		}
+	}
+	bundle, err := job.build()
+	if err != nil {
+		return nil, job.forgetMapOn(err)
+	}
+	return bundle, nil
+}
+
+var _ LoadJob = (*GameAssetsJob)(nil)
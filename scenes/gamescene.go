@@ -5,49 +5,78 @@ import (
 	"image"
 	"image/color"
 	"log"
-	"math"
 
 	"github.com/FunctionPointerXDD/Trader/animations"
+	"github.com/FunctionPointerXDD/Trader/assets"
 	"github.com/FunctionPointerXDD/Trader/camera"
 	"github.com/FunctionPointerXDD/Trader/components"
 	"github.com/FunctionPointerXDD/Trader/constants"
+	"github.com/FunctionPointerXDD/Trader/display"
+	"github.com/FunctionPointerXDD/Trader/ecs"
 	"github.com/FunctionPointerXDD/Trader/entities"
-	"github.com/FunctionPointerXDD/Trader/spritesheet"
+	"github.com/FunctionPointerXDD/Trader/nav"
+	"github.com/FunctionPointerXDD/Trader/render"
+	"github.com/FunctionPointerXDD/Trader/spawner"
+	"github.com/FunctionPointerXDD/Trader/systems"
 	"github.com/FunctionPointerXDD/Trader/tilemap"
-	"github.com/FunctionPointerXDD/Trader/tileset"
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
 	"github.com/hajimehoshi/ebiten/v2/vector"
 )
 
+// DefaultMap is loaded when a GameScene is opened without a map.
+const DefaultMap = assets.SpawnMap
+
+// GameSceneData is the payload for opening a GameScene.
+type GameSceneData struct {
+	Map        string      // logical name of the map to load, DefaultMap if empty
+	SpawnPoint string      // name of the player_start object, the first one if empty
+	Assets     *GameAssets // loaded by a LoadingScene or with LoadGameAssets
+}
+
 type GameScene struct {
-	loaded            bool
-	player            *entities.Player
-	playerSpriteSheet *spritesheet.SpriteSheet
-	enemies           []*entities.Enemy
-	potions           []*entities.Potion
-	tilemapJSON       *tilemap.TilemapJSON
-	tilesets          []tileset.Tileset
-	tilemapImg        *ebiten.Image
-	cam               *camera.Camera
-	colliders         []image.Rectangle
+	loaded      bool
+	data        GameSceneData
+	display     *display.Display
+	assets      *assets.Manager
+	world       *entities.World
+	player      ecs.Entity
+	images      map[string]*ebiten.Image // by asset name, for hot reload
+	tiledMap    *tilemap.Tilemap
+	tilesets    *tilemap.Tilesets
+	mapRenderer *render.MapRenderer
+	renderQueue *render.Queue
+	cam         *camera.Camera
+	colliders   []image.Rectangle
+	planner     *nav.Planner
+	clock       *animations.Clock
+	quitToTitle bool
 }
 
-func NewGameScene() *GameScene {
+func NewGameScene(display *display.Display, manager *assets.Manager, data GameSceneData) (*GameScene, error) {
+	if data.Assets == nil {
+		return nil, fmt.Errorf("game scene needs loaded assets")
+	}
+	if data.Map == "" {
+		data.Map = DefaultMap
+	}
 
 	return &GameScene{
-		player:            nil,
-		playerSpriteSheet: nil,
-		enemies:           make([]*entities.Enemy, 0),
-		potions:           make([]*entities.Potion, 0),
-		tilemapJSON:       nil,
-		tilesets:          nil,
-		tilemapImg:        nil,
-		cam:               nil,
-		colliders:         make([]image.Rectangle, 0),
-		loaded:            false,
-	}
+		data:        data,
+		display:     display,
+		assets:      manager,
+		world:       nil,
+		player:      0,
+		images:      make(map[string]*ebiten.Image),
+		tiledMap:    nil,
+		tilesets:    nil,
+		mapRenderer: nil,
+		renderQueue: render.NewQueue(),
+		cam:         nil,
+		colliders:   make([]image.Rectangle, 0),
+		clock:       nil,
+		loaded:      false,
+	}, nil
 }
 
 func (g *GameScene) IsLoaded() bool {
@@ -59,380 +88,224 @@ func (g *GameScene) Draw(screen *ebiten.Image) {
 
 	screen.Fill(color.RGBA{120, 180, 255, 255}) // blue background
 
-	opts := ebiten.DrawImageOptions{}
+	g.mapRenderer.Draw(screen, g.cam, g.clock.Milliseconds())
+	g.mapRenderer.QueueTallTiles(g.renderQueue, g.cam, g.clock.Milliseconds())
 
-	//loop over the layers
-	for layerIndex, layer := range g.tilemapJSON.Layers {
-		for index, id := range layer.Data {
+	systems.QueueSprites(g.world, g.renderQueue)
 
-			if id == 0 {
-				continue
-			}
-			//catch display position
-			x := index % layer.Width
-			y := index / layer.Width
-
-			x *= 16
-			y *= 16
-
-			img := g.tilesets[layerIndex].Img(id)
+	// everything standing on the map, back to front
+	g.renderQueue.Draw(screen, g.cam)
 
-			opts.GeoM.Translate(float64(x), float64(y))
-			opts.GeoM.Translate(0.0, -(float64(img.Bounds().Dy()) + 16))
-			opts.GeoM.Translate(g.cam.X, g.cam.Y)
-			screen.DrawImage(img, &opts)
-			opts.GeoM.Reset()
-		}
+	for _, collider := range g.colliders {
+		g.strokeWorldRect(screen, collider, color.RGBA{255, 0, 0, 255})
 	}
+	g.world.Hitboxes.Each(func(e ecs.Entity, hitbox *components.Hitbox) {
+		g.strokeWorldRect(screen, hitbox.Rect, color.RGBA{255, 255, 0, 255})
+	})
+}
 
-	opts.GeoM.Translate(g.player.X, g.player.Y)
-	opts.GeoM.Translate(g.cam.X, g.cam.Y)
-
-	playerFrame := 0
-	activeAnim := g.player.ActiveAnimation(int(g.player.Dx), int(g.player.Dy))
-	if activeAnim != nil {
-		playerFrame = activeAnim.Frame()
-	}
-	// draw our player
-	screen.DrawImage(
-		g.player.Img.SubImage(
-			g.playerSpriteSheet.Rect(playerFrame), // if activeAnim is nil, then playFrame is Zero(0), So crop 0 index rect image.
-		).(*ebiten.Image),
-		&opts,
+// strokeWorldRect outlines a rectangle given in world pixels, for debugging.
+func (g *GameScene) strokeWorldRect(screen *ebiten.Image, rect image.Rectangle, clr color.Color) {
+	x, y := g.cam.WorldToScreen(float64(rect.Min.X), float64(rect.Min.Y))
+	vector.StrokeRect(
+		screen,
+		float32(x),
+		float32(y),
+		float32(rect.Dx()*g.cam.Zoom),
+		float32(rect.Dy()*g.cam.Zoom),
+		1.0,
+		clr,
+		true,
 	)
+}
 
-	opts.GeoM.Reset()
-
-	for _, sprite := range g.enemies {
-		opts.GeoM.Translate(sprite.X, sprite.Y)
-		opts.GeoM.Translate(g.cam.X, g.cam.Y)
-		screen.DrawImage(
-			sprite.Img.SubImage(
-				image.Rect(0, 0, 16, 16),
-			).(*ebiten.Image),
-			&opts,
-		)
-		opts.GeoM.Reset()
-	}
+// FirstLoad implements [Scene].
+func (g *GameScene) FirstLoad() {
 
-	opts.GeoM.Reset()
+	bundle := g.data.Assets
+	spawned := bundle.Spawned
 
-	for _, sprite := range g.potions {
-		if sprite.IsUsed {
-			continue
-		}
-		opts.GeoM.Translate(sprite.X, sprite.Y)
-		opts.GeoM.Translate(g.cam.X, g.cam.Y)
-		screen.DrawImage(
-			sprite.Img.SubImage(
-				image.Rect(0, 0, 16, 16),
-			).(*ebiten.Image),
-			&opts,
-		)
-		opts.GeoM.Reset()
+	playerX, playerY := 50.0, 50.0
+	if spawnPoint, ok := spawned.SpawnPoint(g.data.SpawnPoint); ok {
+		playerX, playerY = spawnPoint.X, spawnPoint.Y
 	}
 
-	for _, collider := range g.colliders {
-		vector.StrokeRect(
-			screen,
-			float32(collider.Min.X)+float32(g.cam.X),
-			float32(collider.Min.Y)+float32(g.cam.Y),
-			float32(collider.Dx()),
-			float32(collider.Dy()),
-			1.0,
-			color.RGBA{255, 0, 0, 255},
-			true,
-		)
-	}
+	g.world = bundle.World
+	g.player = entities.NewPlayer(g.world, bundle.PlayerImg, playerX, playerY)
+	g.images[assets.PlayerImage] = bundle.PlayerImg
+	g.images[assets.SkeletonImage] = bundle.SkeletonImg
+	g.images[assets.PotionImage] = bundle.PotionImg
 
+	g.tiledMap = bundle.TiledMap
+	g.tilesets = bundle.Tilesets
+	g.mapRenderer = bundle.MapRenderer
+	g.cam = camera.NewCamera(0.0, 0.0)
+	g.cam.Smoothing = camera.SmoothSpring
+	g.cam.DeadZone = image.Pt(24, 16)
+	g.cam.LookAhead = 24.0
+	g.colliders = append(bundle.Colliders, spawned.Colliders...)
+	g.planner = newPlanner(g.tiledMap, g.colliders)
+	g.clock = animations.NewClock(ebiten.TPS())
+
+	g.loaded = true
 }
 
-// FirstLoad implements [Scene].
-func (g *GameScene) FirstLoad() {
+// OnEnter implements [Scene].
+func (g *GameScene) OnEnter() {
+}
 
-	playerImg, _, err := ebitenutil.NewImageFromFile("assets/images/ninga.png")
-	if err != nil {
-		log.Fatal(err)
-	}
+// OnExit implements [Scene].
+func (g *GameScene) OnExit() {
+}
 
-	skeletonImg, _, err := ebitenutil.NewImageFromFile("assets/images/skeleton.png")
-	if err != nil {
-		log.Fatal(err)
+// OnAssetsChanged implements [AssetReloader]. The player, enemies and camera keep
+// their state; only images, tiles and colliders are replaced. Errors are logged and
+// the old assets kept, so a half-saved file doesn't end the session.
+func (g *GameScene) OnAssetsChanged(names []string) {
+	for _, name := range names {
+		var err error
+		switch name {
+		case g.data.Map:
+			err = g.reloadMap()
+		default:
+			if _, ok := g.images[name]; !ok {
+				continue
+			}
+			err = g.reloadImage(name)
+		}
+		if err != nil {
+			log.Printf("reload %s: %v", name, err)
+			continue
+		}
+		log.Printf("reloaded %s", name)
 	}
+}
 
-	potionImg, _, err := ebitenutil.NewImageFromFile("assets/images/LifePot.png")
+// reloadImage swaps the image called name wherever the scene draws it.
+func (g *GameScene) reloadImage(name string) error {
+	img, err := g.assets.Image(name)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 
-	tilemapImg, _, err := ebitenutil.NewImageFromFile("assets/images/TilesetFloor.png")
-	if err != nil {
-		log.Fatal(err)
+	old := g.images[name]
+	g.world.Sprites.Each(func(e ecs.Entity, sprite *components.Sprite) {
+		if sprite.Img == old {
+			sprite.Img = img
+		}
+	})
+	g.images[name] = img
+	if old != img {
+		old.Deallocate()
 	}
+	return nil
+}
 
-	tilemapJSON, err := tilemap.NewTilemapJSON("assets/maps/spawn.json")
+func (g *GameScene) reloadMap() error {
+	tiledMap, err := g.assets.Tilemap(g.data.Map)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
-
-	tilesets, err := tilemapJSON.GenTilesets()
+	tilesets, err := g.assets.Tilesets(g.data.Map)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
-
-	playerSpriteSheet := spritesheet.NewSpriteSheet(4, 7, 16)
-
-	g.player = &entities.Player{
-		Sprite: &entities.Sprite{
-			Img: playerImg,
-			X:   50.0,
-			Y:   50.0,
-		},
-		Health: 3,
-		Animations: map[entities.PlayerState]*animations.Animation{
-			entities.Up:    animations.NewAnimation(5, 13, 4, 20),
-			entities.Down:  animations.NewAnimation(4, 12, 4, 20),
-			entities.Left:  animations.NewAnimation(6, 14, 4, 20),
-			entities.Right: animations.NewAnimation(7, 15, 4, 20),
-		},
-		CombatComp: components.NewBasicCombat(3, 1),
+	if err := tiledMap.Validate(tilesets); err != nil {
+		return err
 	}
-
-	g.playerSpriteSheet = playerSpriteSheet
-
-	g.enemies = []*entities.Enemy{
-		{
-			Sprite: &entities.Sprite{
-				Img: skeletonImg,
-				X:   100.0,
-				Y:   100.0,
-			},
-			FollowsPlayer: true,
-			CombatComp:    components.NewEnemyCombat(3, 1, 30),
-		},
-		{
-			Sprite: &entities.Sprite{
-				Img: skeletonImg,
-				X:   150.0,
-				Y:   150.0,
-			},
-			FollowsPlayer: false,
-			CombatComp:    components.NewEnemyCombat(3, 1, 30),
-		},
+	colliders, err := tiledMap.Colliders(tilesets)
+	if err != nil {
+		return err
 	}
-
-	g.potions = []*entities.Potion{
-		{
-			Sprite: &entities.Sprite{
-				Img: potionImg,
-				X:   210.0,
-				Y:   100.0,
-			},
-			AmtHeal: 1.0,
-			IsUsed:  false,
-		},
+	mapRenderer, err := render.NewMapRenderer(tiledMap, tilesets)
+	if err != nil {
+		return err
 	}
-
-	g.tilemapJSON = tilemapJSON
-	g.tilemapImg = tilemapImg
-	g.tilesets = tilesets
-	g.cam = camera.NewCamera(0.0, 0.0)
-	g.colliders = []image.Rectangle{
-		image.Rect(100, 100, 116, 116),
+	// 적과 포션은 지금 상태를 유지하고, 맵에 그려진 충돌 영역만 다시 읽는다
+	g.mapRenderer.Dispose()
+	if g.tilesets != tilesets {
+		g.tilesets.Dispose()
 	}
-
-	g.loaded = true
+	g.tiledMap = tiledMap
+	g.tilesets = tilesets
+	g.mapRenderer = mapRenderer
+	g.colliders = append(colliders, spawner.Colliders(tiledMap.Objects())...)
+	g.planner = newPlanner(g.tiledMap, g.colliders)
+	// 이전 맵으로 계획한 경로는 버린다
+	g.world.Paths.Each(func(e ecs.Entity, path *components.Path) {
+		path.Planned = false
+		path.Search = nil
+	})
+	return nil
 }
 
-// OnEnter implements [Scene].
-func (g *GameScene) OnEnter() {
+// navBudget is how many cells A* may expand per tick, shared by every enemy.
+const navBudget = 2000
+
+// newPlanner builds the navigation grid for the map's colliders.
+func newPlanner(tiledMap *tilemap.Tilemap, colliders []image.Rectangle) *nav.Planner {
+	grid := nav.NewGrid(tiledMap.PixelBounds(), tiledMap.TileWidth, colliders)
+	return nav.NewPlanner(grid, navBudget)
 }
 
-// OnExit implements [Scene].
-func (g *GameScene) OnExit() {
+// OnResult implements [ResultReceiver].
+func (g *GameScene) OnResult(from SceneId, result any) {
+	if from == PauseSceneId && result == PauseQuitToTitle {
+		g.quitToTitle = true
+	}
 }
 
 // Update implements [Scene].
-func (g *GameScene) Update() SceneId {
+func (g *GameScene) Update() Next {
+	if g.quitToTitle {
+		return SwitchTo(StartSceneId, Wipe(30))
+	}
 	if inpututil.IsKeyJustPressed(ebiten.KeyQ) {
-		return ExitSceneId
+		return ExitGame()
 	}
 	if inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
-		return PauseSceneId
-	}
-	// react to key presses
-
-	g.player.Dx = 0.0
-	g.player.Dy = 0.0
-	if ebiten.IsKeyPressed(ebiten.KeyRight) {
-		g.player.Dx = 2
-	}
-	if ebiten.IsKeyPressed(ebiten.KeyLeft) {
-		g.player.Dx = -2
-	}
-	if ebiten.IsKeyPressed(ebiten.KeyUp) {
-		g.player.Dy = -2
-	}
-	if ebiten.IsKeyPressed(ebiten.KeyDown) {
-		g.player.Dy = 2
+		return PushScene(PauseSceneId, Transition{}) // 게임 화면은 멈춘 채로 아래에 그려진다
 	}
+	g.clock.Update()
 
-	g.player.X += g.player.Dx
-	CheckCollisionHorizontal(g.player.Sprite, g.colliders)
-
-	g.player.Y += g.player.Dy
-	CheckCollisionVertical(g.player.Sprite, g.colliders)
-
-	activeAnim := g.player.ActiveAnimation(int(g.player.Dx), int(g.player.Dy))
-	if activeAnim != nil {
-		activeAnim.Update()
+	// 클릭하면 커서 쪽으로, 스페이스는 바라보는 방향으로 휘두른다
+	clicked := inpututil.IsMouseButtonJustPressed(ebiten.MouseButton0)
+	screenX, screenY := g.display.CursorPosition()
+	cursorX, cursorY := g.cam.ScreenToWorld(float64(screenX), float64(screenY))
+	attack := systems.PlayerAttack{
+		Pressed: clicked || inpututil.IsKeyJustPressed(ebiten.KeySpace),
+		Aimed:   clicked,
+		AimX:    cursorX,
+		AimY:    cursorY,
 	}
 
-	for _, enemy := range g.enemies {
-
-		enemy.Dx = 0.0
-		enemy.Dy = 0.0
-		if enemy.FollowsPlayer {
-			if enemy.X < g.player.X {
-				enemy.Dx = 0.5
-			} else if enemy.X > g.player.X {
-				enemy.Dx = -0.5
-			}
-			if enemy.Y < g.player.Y {
-				enemy.Dy = 0.5
-			} else if enemy.Y > g.player.Y {
-				enemy.Dy = -0.5
-			}
-		}
-		enemy.X += enemy.Dx
-		CheckCollisionHorizontal(enemy.Sprite, g.colliders)
-		enemy.Y += enemy.Dy
-		CheckCollisionVertical(enemy.Sprite, g.colliders)
+	systems.PlayerInput(g.world)
+	g.planner.BeginTick()
+	systems.AI(g.world, g.colliders, g.planner)
+	systems.Move(g.world, g.colliders)
+	systems.Animate(g.world)
+	systems.Pickup(g.world)
+	if systems.Combat(g.world, g.clock.Tick(), attack) {
+		g.cam.AddTrauma(0.5)
 	}
+	g.world.Flush()
 
-	for _, potion := range g.potions {
-		if !potion.IsUsed && g.player.X > potion.X-16.0 && g.player.X < potion.X+16.0 &&
-			g.player.Y > potion.Y-16.0 && g.player.Y < potion.Y+16.0 {
-			g.player.Health += potion.AmtHeal
-			fmt.Printf("Picked up potion!. Health: %d\n", g.player.Health)
-			potion.IsUsed = true
-		}
+	if inpututil.IsKeyJustPressed(ebiten.KeyEqual) {
+		g.cam.SetZoom(min(g.cam.Zoom+1, 4))
 	}
-
-	clicked := inpututil.IsMouseButtonJustPressed(ebiten.MouseButton0)
-	cX, cY := ebiten.CursorPosition()
-	cX -= int(g.cam.X)
-	cY -= int(g.cam.Y)
-	g.player.CombatComp.Update()
-	pRect := image.Rect(
-		int(g.player.X),
-		int(g.player.Y),
-		int(g.player.X)+constants.Tilesize,
-		int(g.player.Y)+constants.Tilesize,
-	)
-
-	deadEnemies := make(map[int]struct{})
-	for index, enemy := range g.enemies {
-		enemy.CombatComp.Update()
-		rect := image.Rect(
-			int(enemy.X),
-			int(enemy.Y),
-			int(enemy.X)+constants.Tilesize,
-			int(enemy.Y)+constants.Tilesize,
-		)
-
-		if rect.Overlaps(pRect) {
-			if enemy.CombatComp.Attack() {
-				g.player.CombatComp.Damage(enemy.CombatComp.AttackPower())
-				fmt.Println(
-					fmt.Sprintf("player damaged. health: %d\n", g.player.CombatComp.Health()),
-				)
-				if g.player.CombatComp.Health() <= 0 {
-					fmt.Println("player has died!")
-				}
-			}
-		}
-
-		//is cursor in rect?
-		if cX > rect.Min.X && cX < rect.Max.X && cY > rect.Min.Y && cY < rect.Max.Y {
-			if clicked &&
-				//플레이어의 공격(클릭)이 플레이어 중심으로 5칸 이내 범위(원)에 속하면 공격 허용
-				math.Sqrt(
-					math.Pow(
-						float64(cX)-g.player.X+(constants.Tilesize/2),
-						2,
-					)+math.Pow(
-						float64(cY)-g.player.Y+(constants.Tilesize/2),
-						2,
-					),
-				) < constants.Tilesize*5 {
-				fmt.Println("damagind enemy")
-				enemy.CombatComp.Damage(g.player.CombatComp.AttackPower())
-
-				if enemy.CombatComp.Health() <= 0 {
-					deadEnemies[index] = struct{}{} //빈 구조체 타입값 적용
-					fmt.Println("enemy has been eliminated.")
-				}
-			}
-		}
+	if inpututil.IsKeyJustPressed(ebiten.KeyMinus) {
+		g.cam.SetZoom(g.cam.Zoom - 1)
 	}
-	if len(deadEnemies) > 0 {
-		newEnemies := make([]*entities.Enemy, 0)
-		for index, enemy := range g.enemies {
-			if _, isDead := deadEnemies[index]; !isDead {
-				newEnemies = append(newEnemies, enemy)
-			}
-		}
-		g.enemies = newEnemies
+	g.cam.SetScreenSize(g.display.Size())
+	g.cam.Update()
+	if playerPos, ok := g.world.Positions.Get(g.player); ok {
+		g.cam.FollowTarget(playerPos.X+constants.Tilesize/2, playerPos.Y+constants.Tilesize/2)
 	}
+	g.cam.Constrain(g.tiledMap.PixelBounds())
 
-	g.cam.FollowTarget(g.player.X+8, g.player.Y+8, 320, 240)
-	g.cam.Constrain(
-		float64(g.tilemapJSON.Layers[0].Width)*constants.Tilesize,
-		float64(g.tilemapJSON.Layers[0].Height)*constants.Tilesize,
-		320,
-		240,
-	)
-
-	return GameSceneId
+	return StayHere()
 }
 
 var _ Scene = (*GameScene)(nil)
-
-func CheckCollisionHorizontal(sprite *entities.Sprite, colliders []image.Rectangle) {
-	for _, collider := range colliders {
-		if collider.Overlaps(
-			image.Rect(
-				int(sprite.X),
-				int(sprite.Y),
-				int(sprite.X)+16.0,
-				int(sprite.Y)+16.0,
-			),
-		) {
-			if sprite.Dx > 0.0 {
-				sprite.X = float64(collider.Min.X) - 16.0
-			} else if sprite.Dx < 0.0 {
-				sprite.X = float64(collider.Max.X)
-			}
-		}
-	}
-}
-
-func CheckCollisionVertical(sprite *entities.Sprite, colliders []image.Rectangle) {
-	for _, collider := range colliders {
-		if collider.Overlaps(
-			image.Rect(
-				int(sprite.X),
-				int(sprite.Y),
-				int(sprite.X)+16.0,
-				int(sprite.Y)+16.0,
-			),
-		) {
-			if sprite.Dy > 0.0 {
-				sprite.Y = float64(collider.Min.Y) - 16.0
-			} else if sprite.Dy < 0.0 {
-				sprite.Y = float64(collider.Max.Y)
-			}
-		}
-	}
-}
+var _ ResultReceiver = (*GameScene)(nil)
+var _ AssetReloader = (*GameScene)(nil)
@@ -0,0 +1,163 @@
+package scenes
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// LoadStep is one unit of loading work. Run is called on a worker goroutine, so it
+// must not create or draw ebiten images; decode into image.Image instead.
+type LoadStep struct {
+	Name string
+	Run  func() error
+}
+
+// LoadJob is the work done by a LoadingScene. Steps run in order on a worker
+// goroutine; Finish then runs on the main thread to create ebiten images and
+// returns the scene to open with the loaded bundle.
+type LoadJob interface {
+	Steps() []LoadStep
+	Finish() (Next, error)
+}
+
+// LoadingSceneData is the payload for opening a LoadingScene.
+type LoadingSceneData struct {
+	Job  LoadJob
+	Back Next // where to go when the player gives up after an error, none if Stay
+}
+
+type loadProgress struct {
+	done int
+	err  error
+}
+
+type LoadingScene struct {
+	loaded   bool
+	data     LoadingSceneData
+	steps    []LoadStep
+	progress chan loadProgress
+	done     int
+	err      error
+}
+
+func NewLoadingScene(data LoadingSceneData) (*LoadingScene, error) {
+	if data.Job == nil {
+		return nil, fmt.Errorf("loading scene needs a job")
+	}
+	return &LoadingScene{
+		data:   data,
+		loaded: false,
+	}, nil
+}
+
+func (l *LoadingScene) Draw(screen *ebiten.Image) {
+	screen.Fill(color.Black)
+
+	bounds := screen.Bounds()
+	width, height := float32(bounds.Dx()), float32(bounds.Dy())
+	barX, barY, barWidth, barHeight := width*0.1, height*0.5, width*0.8, float32(8)
+
+	progress := float32(1)
+	if len(l.steps) > 0 {
+		progress = float32(l.done) / float32(len(l.steps))
+	}
+	vector.StrokeRect(screen, barX, barY, barWidth, barHeight, 1, color.White, false)
+	vector.FillRect(screen, barX, barY, barWidth*progress, barHeight, color.White, false)
+
+	if l.err != nil {
+		msg := fmt.Sprintf("Loading failed:\n%v\n\nPress R to retry", l.err)
+		if l.data.Back.Action != Stay {
+			msg += "\nPress Escape to go back"
+		}
+		ebitenutil.DebugPrint(screen, msg)
+		return
+	}
+	if l.done < len(l.steps) {
+		ebitenutil.DebugPrint(screen, fmt.Sprintf("Loading %s... (%d/%d)", l.steps[l.done].Name, l.done, len(l.steps)))
+	}
+}
+
+func (l *LoadingScene) FirstLoad() {
+	l.start()
+	l.loaded = true
+}
+
+func (l *LoadingScene) IsLoaded() bool {
+	return l.loaded
+}
+
+func (l *LoadingScene) OnEnter() {
+}
+
+func (l *LoadingScene) OnExit() {
+}
+
+func (l *LoadingScene) Update() Next {
+	if l.err != nil {
+		if inpututil.IsKeyJustPressed(ebiten.KeyR) {
+			l.start()
+		} else if inpututil.IsKeyJustPressed(ebiten.KeyEscape) && l.data.Back.Action != Stay {
+			return l.data.Back
+		}
+		return StayHere()
+	}
+
+	// 워커가 보낸 진행 상황을 막히지 않게 모두 읽는다
+	for draining := true; draining; {
+		select {
+		case p := <-l.progress:
+			l.done = p.done
+			if p.err != nil {
+				l.err = p.err
+				return StayHere()
+			}
+		default:
+			draining = false
+		}
+	}
+
+	if l.done < len(l.steps) {
+		return StayHere()
+	}
+	next, err := l.data.Job.Finish()
+	if err != nil {
+		l.err = err
+		return StayHere()
+	}
+	return next
+}
+
+// start runs the job's steps from the beginning on a new worker goroutine.
+func (l *LoadingScene) start() {
+	l.steps = l.data.Job.Steps()
+	l.progress = make(chan loadProgress, len(l.steps))
+	l.done = 0
+	l.err = nil
+	go runSteps(l.steps, l.progress)
+}
+
+func runSteps(steps []LoadStep, progress chan<- loadProgress) {
+	done := 0
+	// 깨진 에셋 때문에 패닉이 나도 게임 전체가 죽지 않게 에러로 바꾼다
+	defer func() {
+		if r := recover(); r != nil {
+			progress <- loadProgress{done: done, err: fmt.Errorf("%s: %v", steps[done].Name, r)}
+		}
+	}()
+
+	for _, step := range steps {
+		if err := step.Run(); err != nil {
+			progress <- loadProgress{done: done, err: fmt.Errorf("%s: %w", step.Name, err)}
+			return
+		}
+		done++
+		progress <- loadProgress{done: done}
+	}
+}
+
+var _ Scene = (*LoadingScene)(nil)
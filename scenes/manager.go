@@ -0,0 +1,153 @@
+package scenes
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Manager keeps a stack of scenes created from a Registry. Only the top scene updates; it draws on top of
+// every overlay below it, down to the first scene that is not an overlay.
+//
+// OnEnter fires when a scene becomes the top of the stack and OnExit when it stops
+// being the top, whether it was removed or covered by a pushed scene.
+type Manager struct {
+	registry   *Registry
+	stack      []stackEntry
+	transition *activeTransition
+}
+
+type stackEntry struct {
+	id    SceneId
+	scene Scene
+}
+
+func NewManager(registry *Registry, initial SceneId, payload any) (*Manager, error) {
+	m := &Manager{
+		registry: registry,
+	}
+	if err := m.push(initial, payload); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Update runs the top scene, or advances the running transition. It returns
+// ebiten.Termination once the stack is empty.
+func (m *Manager) Update() error {
+	if m.transition != nil {
+		return m.updateTransition()
+	}
+
+	next := m.top().Update()
+	if next.Action == Stay {
+		return nil
+	}
+	if next.Transition.Kind == TransitionNone || next.Transition.Duration <= 0 {
+		return m.apply(next)
+	}
+	m.transition = &activeTransition{
+		Transition: next.Transition,
+		next:       next,
+	}
+	return nil
+}
+
+func (m *Manager) updateTransition() error {
+	t := m.transition
+	t.tick++
+	if !t.applied && t.tick >= t.midpoint() {
+		t.applied = true
+		if err := m.apply(t.next); err != nil {
+			m.transition = nil
+			return err
+		}
+	}
+	if t.done() {
+		m.transition = nil
+	}
+	return nil
+}
+
+func (m *Manager) Draw(screen *ebiten.Image) {
+	if len(m.stack) == 0 {
+		return
+	}
+	for _, entry := range m.stack[m.visibleFrom():] {
+		entry.scene.Draw(screen)
+	}
+	if m.transition != nil {
+		m.transition.draw(screen)
+	}
+}
+
+// AssetsChanged tells every scene on the stack that implements AssetReloader that
+// assets were reloaded, including the frozen ones under an overlay.
+func (m *Manager) AssetsChanged(names []string) {
+	for _, entry := range m.stack {
+		if reloader, ok := entry.scene.(AssetReloader); ok {
+			reloader.OnAssetsChanged(names)
+		}
+	}
+}
+
+// visibleFrom returns the index of the lowest scene that has to be drawn.
+func (m *Manager) visibleFrom() int {
+	for i := len(m.stack) - 1; i > 0; i-- {
+		if !isOverlay(m.stack[i].scene) {
+			return i
+		}
+	}
+	return 0
+}
+
+func (m *Manager) top() Scene {
+	return m.stack[len(m.stack)-1].scene
+}
+
+func (m *Manager) apply(next Next) error {
+	switch next.Action {
+	case Switch:
+		m.top().OnExit()
+		m.stack = m.stack[:len(m.stack)-1]
+		return m.push(next.Scene, next.Payload)
+	case Push:
+		m.top().OnExit()
+		return m.push(next.Scene, next.Payload)
+	case Pop:
+		popped := m.stack[len(m.stack)-1]
+		popped.scene.OnExit()
+		m.stack = m.stack[:len(m.stack)-1]
+		if len(m.stack) == 0 {
+			return ebiten.Termination
+		}
+		if receiver, ok := m.top().(ResultReceiver); ok && next.Payload != nil {
+			receiver.OnResult(popped.id, next.Payload)
+		}
+		m.top().OnEnter()
+	case Exit:
+		for len(m.stack) > 0 {
+			m.top().OnExit()
+			m.stack = m.stack[:len(m.stack)-1]
+		}
+		return ebiten.Termination
+	}
+	return nil
+}
+
+func (m *Manager) push(id SceneId, payload any) error {
+	scene, err := m.registry.Create(id, payload)
+	if err != nil {
+		return err
+	}
+	// if not loaded? then load in
+	if !scene.IsLoaded() {
+		scene.FirstLoad()
+	}
+	m.stack = append(m.stack, stackEntry{id, scene})
+	scene.OnEnter()
+	return nil
+}
+
+func isOverlay(scene Scene) bool {
+	overlay, ok := scene.(Overlay)
+	return ok && overlay.IsOverlay()
+}
@@ -6,6 +6,15 @@ import (
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// PauseResult is returned to the scene below when the pause menu closes.
+type PauseResult uint8
+
+const (
+	PauseResume PauseResult = iota
+	PauseQuitToTitle
 )
 
 type PauseScene struct {
@@ -19,8 +28,10 @@ func NewPauseScene() *PauseScene {
 }
 
 func (p *PauseScene) Draw(screen *ebiten.Image) {
-	screen.Fill(color.RGBA{0, 255, 0, 255})
-	ebitenutil.DebugPrint(screen, "Press Enter to unpause")
+	// 아래에 멈춰 있는 게임 화면을 어둡게 덮는다
+	bounds := screen.Bounds()
+	vector.FillRect(screen, 0, 0, float32(bounds.Dx()), float32(bounds.Dy()), color.NRGBA{0, 0, 0, 160}, false)
+	ebitenutil.DebugPrint(screen, "Press Enter to unpause\nPress T to quit to title")
 }
 
 func (p *PauseScene) FirstLoad() {
@@ -37,11 +48,19 @@ func (p *PauseScene) OnEnter() {
 func (p *PauseScene) OnExit() {
 }
 
-func (p *PauseScene) Update() SceneId {
+func (p *PauseScene) Update() Next {
 	if inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
-		return GameSceneId
+		return PopScene(Transition{}).WithPayload(PauseResume)
 	}
-	return PauseSceneId
+	if inpututil.IsKeyJustPressed(ebiten.KeyT) {
+		return PopScene(Transition{}).WithPayload(PauseQuitToTitle)
+	}
+	return StayHere()
+}
+
+// IsOverlay implements [Overlay].
+func (p *PauseScene) IsOverlay() bool {
+	return true
 }
 
-var _ Scene = (*PauseScene)(nil)
+var _ Overlay = (*PauseScene)(nil)
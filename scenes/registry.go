@@ -0,0 +1,48 @@
+package scenes
+
+import "fmt"
+
+// Factory creates a new scene from the payload it was opened with.
+type Factory func(payload any) (Scene, error)
+
+// Registry maps scene ids to factories, so scenes are created when they are
+// opened and discarded when they leave the stack.
+type Registry struct {
+	factories map[SceneId]Factory
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		factories: make(map[SceneId]Factory),
+	}
+}
+
+func (r *Registry) Register(id SceneId, factory Factory) {
+	r.factories[id] = factory
+}
+
+func (r *Registry) Create(id SceneId, payload any) (Scene, error) {
+	factory, ok := r.factories[id]
+	if !ok {
+		return nil, fmt.Errorf("scenes: unknown scene id %d", id)
+	}
+	scene, err := factory(payload)
+	if err != nil {
+		return nil, fmt.Errorf("scenes: create scene %d: %w", id, err)
+	}
+	return scene, nil
+}
+
+// PayloadAs converts a payload to the type a factory expects. A nil payload gives
+// the zero value, so scenes can be opened without one.
+func PayloadAs[T any](payload any) (T, error) {
+	var zero T
+	if payload == nil {
+		return zero, nil
+	}
+	value, ok := payload.(T)
+	if !ok {
+		return zero, fmt.Errorf("unexpected payload type %T, want %T", payload, zero)
+	}
+	return value, nil
+}
@@ -8,14 +8,76 @@ const (
 	GameSceneId SceneId = iota
 	StartSceneId
 	PauseSceneId
-	ExitSceneId
+	LoadingSceneId
 )
 
 type Scene interface {
-	Update() SceneId
+	Update() Next
 	Draw(screen *ebiten.Image)
 	FirstLoad()
 	OnEnter()
 	OnExit()
 	IsLoaded() bool
 }
+
+// Overlay is implemented by scenes that draw on top of the scene below them
+// (pause, inventory, dialogue). The scenes underneath keep drawing but stop updating.
+type Overlay interface {
+	Scene
+	IsOverlay() bool
+}
+
+type Action uint8
+
+const (
+	Stay   Action = iota // keep running the current scene
+	Switch               // replace the top scene
+	Push                 // put a scene on top of the current one
+	Pop                  // remove the top scene, resuming the one below
+	Exit                 // close every scene and quit the game
+)
+
+// ResultReceiver is implemented by scenes that want the payload of a Pop from the
+// scene above them, e.g. the choice made in a dialogue.
+type ResultReceiver interface {
+	OnResult(from SceneId, result any)
+}
+
+// AssetReloader is implemented by scenes that can swap in assets reloaded from
+// disk while they run. names are the logical names of the changed assets.
+type AssetReloader interface {
+	OnAssetsChanged(names []string)
+}
+
+// Next is returned from Scene.Update to tell the Manager what to do after this tick.
+type Next struct {
+	Action     Action
+	Scene      SceneId // target of Switch and Push
+	Payload    any     // passed to the factory on Switch and Push, to the revealed scene on Pop
+	Transition Transition
+}
+
+func (n Next) WithPayload(payload any) Next {
+	n.Payload = payload
+	return n
+}
+
+func StayHere() Next {
+	return Next{}
+}
+
+func SwitchTo(id SceneId, t Transition) Next {
+	return Next{Action: Switch, Scene: id, Transition: t}
+}
+
+func PushScene(id SceneId, t Transition) Next {
+	return Next{Action: Push, Scene: id, Transition: t}
+}
+
+func PopScene(t Transition) Next {
+	return Next{Action: Pop, Transition: t}
+}
+
+func ExitGame() Next {
+	return Next{Action: Exit}
+}
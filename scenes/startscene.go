@@ -3,6 +3,7 @@ package scenes
 import (
 	"image/color"
 
+	"github.com/FunctionPointerXDD/Trader/assets"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
@@ -10,11 +11,13 @@ import (
 
 type StartScene struct {
 	loaded bool
+	assets *assets.Manager
 }
 
-func NewStartScene() *StartScene {
+func NewStartScene(manager *assets.Manager) *StartScene {
 	return &StartScene{
 		loaded: false,
+		assets: manager,
 	}
 }
 
@@ -37,11 +40,14 @@ func (s *StartScene) OnEnter() {
 func (s *StartScene) OnExit() {
 }
 
-func (s *StartScene) Update() SceneId {
+func (s *StartScene) Update() Next {
 	if inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
-		return GameSceneId
+		return SwitchTo(LoadingSceneId, Fade(30)).WithPayload(LoadingSceneData{
+			Job:  NewGameAssetsJob(s.assets, GameSceneData{Map: DefaultMap}),
+			Back: SwitchTo(StartSceneId, Fade(30)),
+		})
 	}
-	return StartSceneId
+	return StayHere()
 }
 
 var _ Scene = (*StartScene)(nil)
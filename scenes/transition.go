@@ -0,0 +1,84 @@
+package scenes
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+type TransitionKind uint8
+
+const (
+	TransitionNone TransitionKind = iota
+	TransitionFade                // fade to black and back
+	TransitionWipe                // black bar sweeps in from the left, then out to the right
+)
+
+// Transition covers the screen while the scene stack changes. The change is applied
+// at the midpoint, when the screen is fully covered.
+type Transition struct {
+	Kind     TransitionKind
+	Duration int         // ticks
+	Color    color.Color // black if nil
+}
+
+func Fade(ticks int) Transition {
+	return Transition{Kind: TransitionFade, Duration: ticks, Color: color.Black}
+}
+
+func Wipe(ticks int) Transition {
+	return Transition{Kind: TransitionWipe, Duration: ticks, Color: color.Black}
+}
+
+// activeTransition is a Transition in progress, holding the stack change it will apply.
+type activeTransition struct {
+	Transition
+	next    Next
+	tick    int
+	applied bool
+}
+
+func (t *activeTransition) midpoint() int {
+	return t.Duration / 2
+}
+
+func (t *activeTransition) done() bool {
+	return t.tick >= t.Duration
+}
+
+// coverage returns how much of the screen is covered, from 0 to 1, peaking at the midpoint.
+func (t *activeTransition) coverage() float32 {
+	mid := t.midpoint()
+	if t.tick <= mid {
+		if mid == 0 {
+			return 1
+		}
+		return float32(t.tick) / float32(mid)
+	}
+	return float32(t.Duration-t.tick) / float32(t.Duration-mid)
+}
+
+func (t *activeTransition) draw(screen *ebiten.Image) {
+	bounds := screen.Bounds()
+	width, height := float32(bounds.Dx()), float32(bounds.Dy())
+	coverage := t.coverage()
+	clr := t.Color
+	if clr == nil {
+		clr = color.Black
+	}
+
+	switch t.Kind {
+	case TransitionFade:
+		c := color.NRGBAModel.Convert(clr).(color.NRGBA)
+		c.A = uint8(float32(c.A) * coverage)
+		vector.FillRect(screen, 0, 0, width, height, c, false)
+	case TransitionWipe:
+		// 들어올 때는 왼쪽부터 덮고, 나갈 때는 왼쪽부터 걷어낸다
+		if t.tick <= t.midpoint() {
+			vector.FillRect(screen, 0, 0, width*coverage, height, clr, false)
+		} else {
+			vector.FillRect(screen, width*(1-coverage), 0, width*coverage, height, clr, false)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package spawner
+
+import (
+	"fmt"
+	"image"
+	"time"
+
+	"github.com/FunctionPointerXDD/Trader/entities"
+	"github.com/FunctionPointerXDD/Trader/tilemap"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// object classes the spawner understands, as set in Tiled
+const (
+	ClassEnemy       = "enemy"
+	ClassPotion      = "potion"
+	ClassPlayerStart = "player_start"
+	ClassCollider    = "collider"
+)
+
+type Spawner struct {
+	EnemyImg  *ebiten.Image
+	PotionImg *ebiten.Image
+}
+
+// SpawnPoint is a player_start object. Maps with several entrances give each one a name.
+type SpawnPoint struct {
+	Name string
+	X, Y float64
+}
+
+type Spawned struct {
+	SpawnPoints []SpawnPoint
+	Colliders   []image.Rectangle
+}
+
+// SpawnPoint finds a spawn point by name. An empty name picks the first one.
+func (s *Spawned) SpawnPoint(name string) (SpawnPoint, bool) {
+	for _, point := range s.SpawnPoints {
+		if name == "" || point.Name == name {
+			return point, true
+		}
+	}
+	return SpawnPoint{}, false
+}
+
+func NewSpawner(enemyImg, potionImg *ebiten.Image) *Spawner {
+	return &Spawner{
+		EnemyImg:  enemyImg,
+		PotionImg: potionImg,
+	}
+}
+
+// Spawn turns map objects into entities in world. Objects of unknown classes are ignored.
+func (s *Spawner) Spawn(world *entities.World, objects []tilemap.TilemapObject) (*Spawned, error) {
+	spawned := &Spawned{
+		SpawnPoints: make([]SpawnPoint, 0),
+		Colliders:   Colliders(objects),
+	}
+
+	for i := range objects {
+		object := &objects[i]
+		x, y := object.Position()
+
+		switch object.ClassName() {
+		case ClassPlayerStart:
+			for _, point := range spawned.SpawnPoints {
+				if point.Name == object.Name {
+					return nil, fmt.Errorf("object %d: more than one %s named %q", object.Id, ClassPlayerStart, object.Name)
+				}
+			}
+			spawned.SpawnPoints = append(spawned.SpawnPoints, SpawnPoint{
+				Name: object.Name,
+				X:    x,
+				Y:    y,
+			})
+
+		case ClassEnemy:
+			if err := s.enemy(world, object, x, y); err != nil {
+				return nil, err
+			}
+
+		case ClassPotion:
+			heal, err := intProperty(object, "heal", 1)
+			if err != nil {
+				return nil, err
+			}
+			if heal < 0 {
+				return nil, fmt.Errorf("object %d: heal must not be negative", object.Id)
+			}
+			entities.NewPotion(world, s.PotionImg, x, y, heal)
+		}
+	}
+
+	return spawned, nil
+}
+
+// Colliders returns the rectangles of the collider objects.
+func Colliders(objects []tilemap.TilemapObject) []image.Rectangle {
+	colliders := make([]image.Rectangle, 0)
+	for i := range objects {
+		object := &objects[i]
+		if object.ClassName() != ClassCollider {
+			continue
+		}
+		x, y := object.Position()
+		colliders = append(colliders, image.Rect(
+			int(x),
+			int(y),
+			int(x+object.Width),
+			int(y+object.Height),
+		))
+	}
+	return colliders
+}
+
+func (s *Spawner) enemy(world *entities.World, object *tilemap.TilemapObject, x, y float64) error {
+	typeName := "skeleton"
+	if object.Properties.Has("enemy_type") {
+		name, err := object.Properties.String("enemy_type")
+		if err != nil {
+			return fmt.Errorf("object %d: %w", object.Id, err)
+		}
+		typeName = name
+	}
+	enemyType, ok := entities.EnemyTypes[typeName]
+	if !ok {
+		return fmt.Errorf("object %d: unknown enemy_type %q", object.Id, typeName)
+	}
+
+	// 오브젝트에 직접 적은 값이 있으면 타입 기본값보다 우선한다
+	followsPlayer, err := boolProperty(object, "follows_player", true)
+	if err != nil {
+		return err
+	}
+	if !followsPlayer {
+		enemyType.AI.AggroRadius = 0
+	}
+	enemyType.Health, err = intProperty(object, "health", enemyType.Health)
+	if err != nil {
+		return err
+	}
+	enemyType.AttackPower, err = intProperty(object, "attack_power", enemyType.AttackPower)
+	if err != nil {
+		return err
+	}
+	// 예전 attack_cooldown은 틱 단위였으니 조용히 밀리초로 읽지 않는다
+	if object.Properties.Has("attack_cooldown") {
+		return fmt.Errorf("object %d: attack_cooldown was in ticks, set attack_cooldown_ms instead", object.Id)
+	}
+	cooldownMs, err := intProperty(object, "attack_cooldown_ms", int(enemyType.Attack.Cooldown/time.Millisecond))
+	if err != nil {
+		return err
+	}
+	enemyType.Attack.Cooldown = time.Duration(cooldownMs) * time.Millisecond
+	enemyType.AI.FleeHealth, err = intProperty(object, "flee_health", enemyType.AI.FleeHealth)
+	if err != nil {
+		return err
+	}
+
+	entities.NewEnemy(world, s.EnemyImg, x, y, enemyType)
+	return nil
+}
+
+// intProperty returns the object's int property called name, or def when the object doesn't set it.
+func intProperty(object *tilemap.TilemapObject, name string, def int) (int, error) {
+	if !object.Properties.Has(name) {
+		return def, nil
+	}
+	value, err := object.Properties.Int(name)
+	if err != nil {
+		return def, fmt.Errorf("object %d: %w", object.Id, err)
+	}
+	return value, nil
+}
+
+// boolProperty returns the object's bool property called name, or def when the object doesn't set it.
+func boolProperty(object *tilemap.TilemapObject, name string, def bool) (bool, error) {
+	if !object.Properties.Has(name) {
+		return def, nil
+	}
+	value, err := object.Properties.Bool(name)
+	if err != nil {
+		return def, fmt.Errorf("object %d: %w", object.Id, err)
+	}
+	return value, nil
+}
@@ -0,0 +1,212 @@
+package systems
+
+import (
+	"image"
+	"math"
+	"math/rand/v2"
+
+	"github.com/FunctionPointerXDD/Trader/components"
+	"github.com/FunctionPointerXDD/Trader/ecs"
+	"github.com/FunctionPointerXDD/Trader/entities"
+	"github.com/FunctionPointerXDD/Trader/nav"
+)
+
+const (
+	arriveDistance   = 2.0    // close enough to a goal, in world pixels
+	patrolTimeout    = 60 * 5 // ticks before giving up on an unreachable patrol point
+	minIdleTicks     = 60     // idle time between patrols
+	maxIdleTicks     = 60 * 3
+	attackHysteresis = 1.25 // leaves the attack state a bit further out than it enters it
+	repathTicks      = 30   // plans a path to a moving goal again after this many ticks
+)
+
+// AI runs the state machines of computer-controlled entities and sets their velocity.
+// They notice the player within their aggro radius when no collider blocks the view,
+// keep chasing while they can see them, and walk home when they lose them or get
+// too far from home. Entities with a Path walk around obstacles using planner.
+func AI(w *entities.World, colliders []image.Rectangle, planner *nav.Planner) {
+	player, playerPos, hasPlayer := w.Player()
+	var playerX, playerY float64
+	if hasPlayer {
+		playerX, playerY = center(w, player, playerPos)
+	}
+
+	ecs.Join3(w.AI, w.Positions, w.Velocities, func(e ecs.Entity, ai *components.AI, pos *components.Position, vel *components.Velocity) {
+		ai.StateTicks++
+		x, y := center(w, e, pos)
+
+		distPlayer := math.Hypot(playerX-x, playerY-y)
+		distHome := math.Hypot(ai.HomeX-x, ai.HomeY-y)
+		visible := hasPlayer && lineOfSight(x, y, playerX, playerY, colliders)
+		notices := visible && ai.AggroRadius > 0 && distPlayer <= ai.AggroRadius
+		hurt := false
+		if combat, ok := w.Combat.Get(e); ok {
+			hurt = ai.FleeHealth > 0 && combat.Health() <= ai.FleeHealth
+		}
+
+		switch ai.State {
+		case components.AIIdle, components.AIPatrol:
+			if notices && hurt {
+				ai.SetState(components.AIFlee)
+			} else if notices {
+				ai.GoalX, ai.GoalY = playerX, playerY
+				ai.SetState(components.AIChase)
+			} else if ai.State == components.AIIdle && ai.PatrolRadius > 0 && ai.StateTicks >= ai.IdleTicks {
+				angle := rand.Float64() * 2 * math.Pi
+				radius := rand.Float64() * ai.PatrolRadius
+				ai.GoalX, ai.GoalY = ai.HomeX+math.Cos(angle)*radius, ai.HomeY+math.Sin(angle)*radius
+				ai.SetState(components.AIPatrol)
+			} else if ai.State == components.AIPatrol && (arrived(x, y, ai.GoalX, ai.GoalY) || ai.StateTicks > patrolTimeout) {
+				ai.IdleTicks = minIdleTicks + rand.IntN(maxIdleTicks-minIdleTicks)
+				ai.SetState(components.AIIdle)
+			}
+
+		case components.AIChase:
+			if visible {
+				ai.GoalX, ai.GoalY = playerX, playerY
+			}
+			if hurt {
+				ai.SetState(components.AIFlee)
+			} else if distHome > ai.LeashDistance {
+				ai.SetState(components.AIReturn)
+			} else if visible && distPlayer <= ai.AttackRange {
+				ai.SetState(components.AIAttack)
+			} else if !visible && arrived(x, y, ai.GoalX, ai.GoalY) {
+				ai.SetState(components.AIReturn) // 마지막으로 본 곳까지 왔는데 없으면 포기
+			}
+
+		case components.AIAttack:
+			if hurt {
+				ai.SetState(components.AIFlee)
+			} else if !visible || distPlayer > ai.AttackRange*attackHysteresis {
+				ai.GoalX, ai.GoalY = playerX, playerY
+				ai.SetState(components.AIChase)
+			}
+
+		case components.AIFlee:
+			if !hasPlayer || distPlayer > ai.AggroRadius*1.5 || distHome > ai.LeashDistance {
+				ai.SetState(components.AIReturn)
+			}
+
+		case components.AIReturn:
+			// 집에 돌아가는 동안은 플레이어를 무시한다
+			if arrived(x, y, ai.HomeX, ai.HomeY) {
+				ai.IdleTicks = minIdleTicks + rand.IntN(maxIdleTicks-minIdleTicks)
+				ai.SetState(components.AIIdle)
+			}
+		}
+
+		path, _ := w.Paths.Get(e)
+		reachable := true
+		vel.Dx, vel.Dy = 0, 0
+		switch ai.State {
+		case components.AIPatrol:
+			vel.Dx, vel.Dy, reachable = navigate(planner, path, x, y, ai.GoalX, ai.GoalY, ai.PatrolSpeed)
+		case components.AIChase:
+			vel.Dx, vel.Dy, reachable = navigate(planner, path, x, y, ai.GoalX, ai.GoalY, ai.Speed)
+		case components.AIFlee:
+			vel.Dx, vel.Dy = steer(playerX, playerY, x, y, ai.Speed)
+		case components.AIReturn:
+			vel.Dx, vel.Dy, reachable = navigate(planner, path, x, y, ai.HomeX, ai.HomeY, ai.Speed)
+		}
+
+		// 갈 수 없는 곳이면 포기한다
+		if !reachable {
+			switch ai.State {
+			case components.AIChase:
+				ai.SetState(components.AIReturn)
+			case components.AIPatrol, components.AIReturn:
+				ai.IdleTicks = minIdleTicks + rand.IntN(maxIdleTicks-minIdleTicks)
+				ai.SetState(components.AIIdle)
+			}
+		}
+	})
+}
+
+// navigate returns the velocity to walk from x, y toward goalX, goalY. It walks
+// straight when nothing is in the way and follows a planned path otherwise. It
+// reports false when the goal can't be reached.
+func navigate(planner *nav.Planner, path *components.Path, x, y, goalX, goalY, speed float64) (float64, float64, bool) {
+	if path == nil || planner == nil {
+		dx, dy := steer(x, y, goalX, goalY, speed)
+		return dx, dy, true
+	}
+	grid := planner.Grid()
+	if grid.ClearLine(x, y, goalX, goalY) {
+		path.Planned = false
+		path.Search = nil
+		dx, dy := steer(x, y, goalX, goalY, speed)
+		return dx, dy, true
+	}
+
+	path.Age++
+	goalCell := grid.CellAt(goalX, goalY)
+	if path.Search == nil && (!path.Planned || path.GoalCell != goalCell || path.Age > repathTicks) {
+		path.Search = planner.Search(x, y, goalX, goalY)
+	}
+	if path.Search != nil {
+		// 예산이 다 떨어졌으면 다음 틱에 이어서 찾고, 그동안은 이전 경로를 따라간다
+		if waypoints, done := planner.FindPath(path.Search); done {
+			path.Waypoints = waypoints
+			path.Next = 0
+			path.GoalCell = path.Search.Goal()
+			path.Planned = true
+			path.Age = 0
+			path.Search = nil
+		}
+	}
+	if !path.Planned {
+		return 0, 0, true
+	}
+	if len(path.Waypoints) == 0 {
+		return 0, 0, false
+	}
+
+	// 도착했거나 그 다음 지점이 바로 보이면 건너뛴다
+	for path.Next < len(path.Waypoints) {
+		waypoint := path.Waypoints[path.Next]
+		if arrived(x, y, float64(waypoint.X), float64(waypoint.Y)) {
+			path.Next++
+			continue
+		}
+		if path.Next+1 < len(path.Waypoints) {
+			next := path.Waypoints[path.Next+1]
+			if grid.ClearLine(x, y, float64(next.X), float64(next.Y)) {
+				path.Next++
+				continue
+			}
+		}
+		break
+	}
+	if path.Next >= len(path.Waypoints) {
+		path.Planned = false // 끝까지 왔는데도 막혀 있으면 다시 계획한다
+		return 0, 0, true
+	}
+	waypoint := path.Waypoints[path.Next]
+	dx, dy := steer(x, y, float64(waypoint.X), float64(waypoint.Y), speed)
+	return dx, dy, true
+}
+
+// center returns the middle of the entity's collider, or its position if it has none.
+func center(w *entities.World, e ecs.Entity, pos *components.Position) (float64, float64) {
+	if body, ok := w.Colliders.Get(e); ok {
+		return pos.X + body.Width/2, pos.Y + body.Height/2
+	}
+	return pos.X, pos.Y
+}
+
+func arrived(x, y, goalX, goalY float64) bool {
+	return math.Hypot(goalX-x, goalY-y) <= arriveDistance
+}
+
+// steer returns a velocity of length speed pointing from x, y to goalX, goalY,
+// slowing down so it doesn't overshoot.
+func steer(x, y, goalX, goalY, speed float64) (float64, float64) {
+	dx, dy := goalX-x, goalY-y
+	dist := math.Hypot(dx, dy)
+	if dist == 0 {
+		return 0, 0
+	}
+	step := min(speed, dist)
+	return dx / dist * step, dy / dist * step
+}
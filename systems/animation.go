@@ -0,0 +1,16 @@
+package systems
+
+import (
+	"github.com/FunctionPointerXDD/Trader/components"
+	"github.com/FunctionPointerXDD/Trader/ecs"
+	"github.com/FunctionPointerXDD/Trader/entities"
+)
+
+// Animate advances the animation of every moving entity.
+func Animate(w *entities.World) {
+	ecs.Join2(w.Animations, w.Velocities, func(e ecs.Entity, anim *components.Animation, vel *components.Velocity) {
+		if active := anim.Active(vel.Dx, vel.Dy); active != nil {
+			active.Update()
+		}
+	})
+}
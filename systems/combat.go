@@ -0,0 +1,123 @@
+package systems
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/FunctionPointerXDD/Trader/components"
+	"github.com/FunctionPointerXDD/Trader/ecs"
+	"github.com/FunctionPointerXDD/Trader/entities"
+)
+
+// PlayerAttack is the player's attack input for one tick.
+type PlayerAttack struct {
+	Pressed    bool
+	Aimed      bool    // swing toward AimX, AimY instead of where the player faces
+	AimX, AimY float64 // world pixels
+}
+
+// Combat advances attack timers by dt and resolves melee. attack starts a swing of
+// the player's, turning them toward the aim if it has one. Enemies in their attack state turn to the player and swing when the
+// player is in reach. A swing puts a hitbox in front of the attacker for the active
+// phase of its attack; it damages and knocks back each hurtbox of the other team
+// once. Enemies that die are despawned. It reports whether the player took damage
+// this tick.
+func Combat(w *entities.World, dt time.Duration, attack PlayerAttack) (playerHit bool) {
+	w.Combat.Each(func(e ecs.Entity, combat components.Combat) {
+		combat.Update(dt)
+	})
+
+	player, playerPos, hasPlayer := w.Player()
+	if hasPlayer {
+		playerX, playerY := center(w, player, playerPos)
+		if playerCombat, ok := w.Combat.Get(player); ok && attack.Pressed && playerCombat.Attack() && attack.Aimed {
+			w.Facings.Add(player, facingToward(attack.AimX-playerX, attack.AimY-playerY))
+		}
+
+		ecs.Join3(w.AI, w.Combat, w.Positions, func(e ecs.Entity, ai *components.AI, combat components.Combat, pos *components.Position) {
+			x, y := center(w, e, pos)
+			if ai.State != components.AIAttack || math.Hypot(playerX-x, playerY-y) > ai.AttackRange {
+				return
+			}
+			// 휘두르기 시작할 때 방향을 정하므로 준비 동작 동안 옆으로 피할 수 있다
+			if combat.Attack() && w.Facings.Has(e) {
+				w.Facings.Add(e, facingToward(playerX-x, playerY-y))
+			}
+		})
+	}
+
+	swing(w)
+
+	w.Hitboxes.Each(func(attacker ecs.Entity, hitbox *components.Hitbox) {
+		ecs.Join3(w.Hurtboxes, w.Combat, w.Positions, func(e ecs.Entity, hurtbox *components.Hurtbox, combat components.Combat, pos *components.Position) {
+			if hurtbox.Team == hitbox.Team || !hitbox.Rect.Overlaps(hurtbox.Rect(pos)) {
+				return
+			}
+			if !hitbox.Hit(e) || !combat.Damage(hitbox.Damage) {
+				return
+			}
+			dx, dy := hitbox.Facing.Vector()
+			w.Knockbacks.Add(e, &components.Knockback{Dx: dx * hitbox.Knockback, Dy: dy * hitbox.Knockback})
+
+			if e == player {
+				playerHit = true
+				fmt.Printf("player damaged. health: %d\n", combat.Health())
+				if combat.Health() <= 0 {
+					fmt.Println("player has died!")
+				}
+				return
+			}
+			fmt.Println("damaging enemy")
+			if combat.Health() <= 0 {
+				fmt.Println("enemy has been eliminated.")
+				w.Despawn(e)
+			}
+		})
+	})
+
+	return playerHit
+}
+
+// swing puts a hitbox on every attacker whose attack just struck, keeps it in front
+// of the attacker while the attack is active, and takes it away afterwards.
+func swing(w *entities.World) {
+	ecs.Join3(w.Combat, w.Melee, w.Positions, func(e ecs.Entity, combat components.Combat, melee *components.Melee, pos *components.Position) {
+		if combat.Strike() {
+			facing, _ := w.Facings.Get(e)
+			w.Hitboxes.Add(e, components.NewHitbox(facing, team(w, e), combat.AttackPower(), melee.Knockback))
+		}
+		hitbox, ok := w.Hitboxes.Get(e)
+		if !ok {
+			return
+		}
+		body, ok := w.Colliders.Get(e)
+		if !ok || combat.Phase() != components.AttackActive {
+			w.Hitboxes.Remove(e)
+			return
+		}
+		hitbox.Rect = melee.Area(body.Rect(pos), hitbox.Facing)
+	})
+}
+
+// team returns the side e is on, from its hurtbox, or from who controls it if it
+// can't be hit.
+func team(w *entities.World, e ecs.Entity) components.Team {
+	if hurtbox, ok := w.Hurtboxes.Get(e); ok {
+		return hurtbox.Team
+	}
+	if w.PlayerInput.Has(e) {
+		return components.TeamPlayer
+	}
+	return components.TeamEnemy
+}
+
+// facingToward returns the direction of dx, dy along its longer axis.
+func facingToward(dx, dy float64) components.Facing {
+	if math.Abs(dx) >= math.Abs(dy) {
+		facing, _ := components.FacingOf(dx, 0)
+		return facing
+	}
+	facing, _ := components.FacingOf(0, dy)
+	return facing
+}
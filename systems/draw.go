@@ -0,0 +1,44 @@
+package systems
+
+import (
+	"time"
+
+	"github.com/FunctionPointerXDD/Trader/components"
+	"github.com/FunctionPointerXDD/Trader/ecs"
+	"github.com/FunctionPointerXDD/Trader/entities"
+	"github.com/FunctionPointerXDD/Trader/render"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// blinkPeriod is how long an invulnerable sprite stays shown, then hidden.
+const blinkPeriod = 80 * time.Millisecond
+
+// QueueSprites pushes every sprite into the render queue, sorted by the bottom of its
+// image. Invulnerable entities blink.
+func QueueSprites(w *entities.World, queue *render.Queue) {
+	ecs.Join2(w.Sprites, w.Positions, func(e ecs.Entity, sprite *components.Sprite, pos *components.Position) {
+		if combat, ok := w.Combat.Get(e); ok && combat.InvulnerableFor()/blinkPeriod%2 == 1 {
+			return
+		}
+
+		src := sprite.Src
+		if anim, ok := w.Animations.Get(e); ok {
+			// 멈춰 있으면 0번 프레임
+			frame := 0
+			if vel, ok := w.Velocities.Get(e); ok {
+				if active := anim.Active(vel.Dx, vel.Dy); active != nil {
+					frame = active.Frame()
+				}
+			}
+			src = anim.Sheet.Rect(frame)
+		}
+
+		geoM := ebiten.GeoM{}
+		geoM.Translate(pos.X, pos.Y)
+		queue.Push(
+			sprite.Img.SubImage(src).(*ebiten.Image),
+			geoM,
+			pos.Y+float64(src.Dy()),
+		)
+	})
+}
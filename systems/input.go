@@ -0,0 +1,28 @@
+package systems
+
+import (
+	"github.com/FunctionPointerXDD/Trader/components"
+	"github.com/FunctionPointerXDD/Trader/ecs"
+	"github.com/FunctionPointerXDD/Trader/entities"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// PlayerInput sets the velocity of player-controlled entities from the arrow keys.
+func PlayerInput(w *entities.World) {
+	ecs.Join2(w.PlayerInput, w.Velocities, func(e ecs.Entity, input *components.PlayerInput, vel *components.Velocity) {
+		vel.Dx, vel.Dy = 0, 0
+
+		if ebiten.IsKeyPressed(ebiten.KeyRight) {
+			vel.Dx = input.Speed
+		}
+		if ebiten.IsKeyPressed(ebiten.KeyLeft) {
+			vel.Dx = -input.Speed
+		}
+		if ebiten.IsKeyPressed(ebiten.KeyUp) {
+			vel.Dy = -input.Speed
+		}
+		if ebiten.IsKeyPressed(ebiten.KeyDown) {
+			vel.Dy = input.Speed
+		}
+	})
+}
@@ -0,0 +1,74 @@
+package systems
+
+import (
+	"image"
+	"math"
+
+	"github.com/FunctionPointerXDD/Trader/components"
+	"github.com/FunctionPointerXDD/Trader/ecs"
+	"github.com/FunctionPointerXDD/Trader/entities"
+)
+
+const (
+	knockbackDecay = 0.8 // knockback kept each tick
+	minKnockback   = 0.1 // below this the push is over, in pixels per tick
+)
+
+// Move applies velocities and knockback. Entities with a Collider are stopped by the
+// map colliders, one axis at a time so they slide along walls. Entities with a
+// Facing turn to where they walk.
+func Move(w *entities.World, colliders []image.Rectangle) {
+	ecs.Join2(w.Velocities, w.Positions, func(e ecs.Entity, vel *components.Velocity, pos *components.Position) {
+		if facing, ok := components.FacingOf(vel.Dx, vel.Dy); ok && w.Facings.Has(e) {
+			w.Facings.Add(e, facing)
+		}
+
+		dx, dy := vel.Dx, vel.Dy
+		if push, ok := w.Knockbacks.Get(e); ok {
+			dx += push.Dx
+			dy += push.Dy
+			push.Dx *= knockbackDecay
+			push.Dy *= knockbackDecay
+			if math.Hypot(push.Dx, push.Dy) < minKnockback {
+				w.Knockbacks.Remove(e)
+			}
+		}
+		body, solid := w.Colliders.Get(e)
+
+		pos.X += dx
+		if solid {
+			resolveHorizontal(pos, dx, body, colliders)
+		}
+
+		pos.Y += dy
+		if solid {
+			resolveVertical(pos, dy, body, colliders)
+		}
+	})
+}
+
+func resolveHorizontal(pos *components.Position, dx float64, body *components.Collider, colliders []image.Rectangle) {
+	for _, collider := range colliders {
+		if !collider.Overlaps(body.Rect(pos)) {
+			continue
+		}
+		if dx > 0.0 {
+			pos.X = float64(collider.Min.X) - body.Width
+		} else if dx < 0.0 {
+			pos.X = float64(collider.Max.X)
+		}
+	}
+}
+
+func resolveVertical(pos *components.Position, dy float64, body *components.Collider, colliders []image.Rectangle) {
+	for _, collider := range colliders {
+		if !collider.Overlaps(body.Rect(pos)) {
+			continue
+		}
+		if dy > 0.0 {
+			pos.Y = float64(collider.Min.Y) - body.Height
+		} else if dy < 0.0 {
+			pos.Y = float64(collider.Max.Y)
+		}
+	}
+}
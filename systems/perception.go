@@ -0,0 +1,44 @@
+package systems
+
+import "image"
+
+// lineOfSight reports whether the segment between two points crosses none of the colliders.
+func lineOfSight(x0, y0, x1, y1 float64, colliders []image.Rectangle) bool {
+	for _, collider := range colliders {
+		if segmentHitsRect(x0, y0, x1, y1, collider) {
+			return false
+		}
+	}
+	return true
+}
+
+// segmentHitsRect clips the segment against the rectangle (Liang–Barsky).
+func segmentHitsRect(x0, y0, x1, y1 float64, r image.Rectangle) bool {
+	dx, dy := x1-x0, y1-y0
+	tMin, tMax := 0.0, 1.0
+
+	clip := func(p, q float64) bool {
+		if p == 0 {
+			return q >= 0 // 평행한 경우: 사각형 안쪽 띠에 있어야 한다
+		}
+		t := q / p
+		if p < 0 {
+			if t > tMax {
+				return false
+			}
+			tMin = max(tMin, t)
+		} else {
+			if t < tMin {
+				return false
+			}
+			tMax = min(tMax, t)
+		}
+		return true
+	}
+
+	return clip(-dx, x0-float64(r.Min.X)) &&
+		clip(dx, float64(r.Max.X)-x0) &&
+		clip(-dy, y0-float64(r.Min.Y)) &&
+		clip(dy, float64(r.Max.Y)-y0) &&
+		tMin < tMax
+}
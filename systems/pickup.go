@@ -0,0 +1,33 @@
+package systems
+
+import (
+	"fmt"
+
+	"github.com/FunctionPointerXDD/Trader/components"
+	"github.com/FunctionPointerXDD/Trader/ecs"
+	"github.com/FunctionPointerXDD/Trader/entities"
+)
+
+// Pickup lets the player consume the pickups they touch.
+func Pickup(w *entities.World) {
+	player, playerPos, ok := w.Player()
+	if !ok {
+		return
+	}
+	playerBody, ok := w.Colliders.Get(player)
+	if !ok {
+		return
+	}
+	playerRect := playerBody.Rect(playerPos)
+
+	ecs.Join3(w.Pickups, w.Positions, w.Colliders, func(e ecs.Entity, pickup *components.Pickup, pos *components.Position, body *components.Collider) {
+		if !body.Rect(pos).Overlaps(playerRect) {
+			return
+		}
+		if combat, ok := w.Combat.Get(player); ok && pickup.Heal > 0 {
+			combat.Heal(pickup.Heal)
+			fmt.Printf("Picked up potion!. Health: %d\n", combat.Health())
+		}
+		w.Despawn(e)
+	})
+}
@@ -0,0 +1,140 @@
+package tilemap
+
+import (
+	"image"
+)
+
+// Colliders builds the collision rectangles of the tile layers in map pixels, from the
+// collision shapes of their tiles. Neighbouring rectangles are merged into larger ones
+// so collision checks stay cheap on big maps.
+func (t *Tilemap) Colliders(tilesets *Tilesets) ([]image.Rectangle, error) {
+	bounds := t.Bounds()
+	cells := make([]bool, bounds.Dx()*bounds.Dy()) // cells fully covered by a shape
+	shapes := make([]image.Rectangle, 0)           // everything else
+
+	for _, layer := range t.Layers {
+		for index, gid := range layer.Data {
+			if gid == 0 {
+				continue
+			}
+			tile, err := tilesets.Tile(gid)
+			if err != nil {
+				return nil, err
+			}
+			collision := tile.Tileset.Collision(tile.Id)
+			if len(collision) == 0 {
+				continue
+			}
+
+			x := layer.StartX + index%layer.Width
+			y := layer.StartY + index/layer.Width
+			cell := image.Rect(x*t.TileWidth, y*t.TileHeight, (x+1)*t.TileWidth, (y+1)*t.TileHeight)
+
+			// same bottom-left anchoring as drawing
+			_, tileHeight := tile.Size()
+			origin := image.Pt(cell.Min.X, cell.Max.Y-tileHeight)
+
+			for _, shape := range collision {
+				rect := tile.transformRect(shape).Add(origin)
+				if rect == cell {
+					cells[(y-bounds.Min.Y)*bounds.Dx()+x-bounds.Min.X] = true
+					continue
+				}
+				shapes = append(shapes, rect)
+			}
+		}
+	}
+
+	colliders := mergeCells(cells, bounds, t.TileWidth, t.TileHeight)
+	colliders = append(colliders, shapes...)
+
+	return mergeRects(colliders), nil
+}
+
+// transformRect applies the tile's flips to a rectangle in the tile image's coordinates.
+func (t *Tile) transformRect(rect image.Rectangle) image.Rectangle {
+	w := t.Img.Bounds().Dx()
+	h := t.Img.Bounds().Dy()
+
+	if t.FlipDiag {
+		rect = image.Rect(rect.Min.Y, rect.Min.X, rect.Max.Y, rect.Max.X)
+		w, h = h, w
+	}
+	if t.FlipH {
+		rect = image.Rect(w-rect.Max.X, rect.Min.Y, w-rect.Min.X, rect.Max.Y)
+	}
+	if t.FlipV {
+		rect = image.Rect(rect.Min.X, h-rect.Max.Y, rect.Max.X, h-rect.Min.Y)
+	}
+	return rect
+}
+
+// mergeCells turns a grid of solid cells into rectangles: runs of cells along each row,
+// grown downward while the row below has a run with the same span.
+func mergeCells(cells []bool, bounds image.Rectangle, tileWidth, tileHeight int) []image.Rectangle {
+	rects := make([]image.Rectangle, 0)
+	open := make(map[[2]int]int) // run span in the previous row -> index into rects
+
+	for row := 0; row < bounds.Dy(); row++ {
+		next := make(map[[2]int]int)
+		for col := 0; col < bounds.Dx(); {
+			if !cells[row*bounds.Dx()+col] {
+				col++
+				continue
+			}
+			start := col
+			for col < bounds.Dx() && cells[row*bounds.Dx()+col] {
+				col++
+			}
+			span := [2]int{start, col}
+
+			if index, ok := open[span]; ok {
+				rects[index].Max.Y += tileHeight
+				next[span] = index
+				continue
+			}
+			x := bounds.Min.X + start
+			y := bounds.Min.Y + row
+			rects = append(rects, image.Rect(x*tileWidth, y*tileHeight, (bounds.Min.X+col)*tileWidth, (y+1)*tileHeight))
+			next[span] = len(rects) - 1
+		}
+		open = next
+	}
+
+	return rects
+}
+
+// mergeRects drops rectangles contained in others and joins pairs that share a full edge,
+// until nothing changes.
+func mergeRects(rects []image.Rectangle) []image.Rectangle {
+	for merged := true; merged; {
+		merged = false
+		for i := 0; i < len(rects) && !merged; i++ {
+			for j := i + 1; j < len(rects); j++ {
+				union, ok := joinRects(rects[i], rects[j])
+				if !ok {
+					continue
+				}
+				rects[i] = union
+				rects = append(rects[:j], rects[j+1:]...)
+				merged = true
+				break
+			}
+		}
+	}
+	return rects
+}
+
+func joinRects(a, b image.Rectangle) (image.Rectangle, bool) {
+	switch {
+	case b.In(a):
+		return a, true
+	case a.In(b):
+		return b, true
+	case a.Min.X == b.Min.X && a.Max.X == b.Max.X && (a.Max.Y == b.Min.Y || b.Max.Y == a.Min.Y):
+		return a.Union(b), true
+	case a.Min.Y == b.Min.Y && a.Max.Y == b.Max.Y && (a.Max.X == b.Min.X || b.Max.X == a.Min.X):
+		return a.Union(b), true
+	}
+	return image.Rectangle{}, false
+}
@@ -0,0 +1,125 @@
+package tilemap
+
+import (
+	"cmp"
+	"image"
+	"slices"
+	"testing"
+)
+
+func sortRects(rects []image.Rectangle) []image.Rectangle {
+	sorted := slices.Clone(rects)
+	slices.SortFunc(sorted, func(a, b image.Rectangle) int {
+		return cmp.Or(
+			cmp.Compare(a.Min.Y, b.Min.Y),
+			cmp.Compare(a.Min.X, b.Min.X),
+			cmp.Compare(a.Max.Y, b.Max.Y),
+			cmp.Compare(a.Max.X, b.Max.X),
+		)
+	})
+	return sorted
+}
+
+func TestMergeCells(t *testing.T) {
+	tests := []struct {
+		name   string
+		rows   []string // # is solid
+		origin image.Point
+		want   []image.Rectangle
+	}{
+		{
+			name: "empty",
+			rows: []string{"...", "..."},
+			want: []image.Rectangle{},
+		},
+		{
+			name: "row run",
+			rows: []string{".##.", "...."},
+			want: []image.Rectangle{image.Rect(16, 0, 48, 16)},
+		},
+		{
+			name: "block grows down",
+			rows: []string{"##.", "##.", "..."},
+			want: []image.Rectangle{image.Rect(0, 0, 32, 32)},
+		},
+		{
+			name: "different spans stay apart",
+			rows: []string{"##", "#."},
+			want: []image.Rectangle{image.Rect(0, 0, 32, 16), image.Rect(0, 16, 16, 32)},
+		},
+		{
+			name: "gap row splits",
+			rows: []string{"#", ".", "#"},
+			want: []image.Rectangle{image.Rect(0, 0, 16, 16), image.Rect(0, 32, 16, 48)},
+		},
+		{
+			name:   "offset bounds",
+			rows:   []string{"#"},
+			origin: image.Pt(-2, 3),
+			want:   []image.Rectangle{image.Rect(-32, 48, -16, 64)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bounds := image.Rect(0, 0, len(tt.rows[0]), len(tt.rows)).Add(tt.origin)
+			cells := make([]bool, 0, bounds.Dx()*bounds.Dy())
+			for _, row := range tt.rows {
+				for _, c := range row {
+					cells = append(cells, c == '#')
+				}
+			}
+
+			got := sortRects(mergeCells(cells, bounds, 16, 16))
+			if !slices.Equal(got, sortRects(tt.want)) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeRects(t *testing.T) {
+	tests := []struct {
+		name  string
+		rects []image.Rectangle
+		want  []image.Rectangle
+	}{
+		{
+			name:  "contained",
+			rects: []image.Rectangle{image.Rect(0, 0, 32, 32), image.Rect(8, 8, 16, 16)},
+			want:  []image.Rectangle{image.Rect(0, 0, 32, 32)},
+		},
+		{
+			name:  "shared vertical edge",
+			rects: []image.Rectangle{image.Rect(0, 0, 16, 16), image.Rect(16, 0, 32, 16)},
+			want:  []image.Rectangle{image.Rect(0, 0, 32, 16)},
+		},
+		{
+			name:  "shared horizontal edge",
+			rects: []image.Rectangle{image.Rect(0, 16, 16, 32), image.Rect(0, 0, 16, 16)},
+			want:  []image.Rectangle{image.Rect(0, 0, 16, 32)},
+		},
+		{
+			name:  "partial edge is kept apart",
+			rects: []image.Rectangle{image.Rect(0, 0, 16, 16), image.Rect(16, 8, 32, 24)},
+			want:  []image.Rectangle{image.Rect(0, 0, 16, 16), image.Rect(16, 8, 32, 24)},
+		},
+		{
+			name: "chain merges until stable",
+			rects: []image.Rectangle{
+				image.Rect(0, 0, 16, 16),
+				image.Rect(32, 0, 48, 16),
+				image.Rect(16, 0, 32, 16),
+				image.Rect(0, 16, 48, 32),
+			},
+			want: []image.Rectangle{image.Rect(0, 0, 48, 32)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := sortRects(mergeRects(slices.Clone(tt.rects)))
+			if !slices.Equal(got, sortRects(tt.want)) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
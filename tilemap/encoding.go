@@ -0,0 +1,141 @@
+package tilemap
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrZstdUnsupported is returned for layers saved with zstd compression, which the
+// standard library cannot decode. Re-save the map with zlib or gzip compression instead.
+var ErrZstdUnsupported = errors.New("zstd compressed layer data is not supported, use zlib or gzip")
+
+// UnmarshalJSON decodes the layer, expanding base64 and compressed tile data into Data.
+// The chunks of infinite maps are merged into Data as well.
+func (l *TilemapLayer) UnmarshalJSON(contents []byte) error {
+	// alias drops the UnmarshalJSON method, so this doesn't recurse
+	type layerAlias TilemapLayer
+	var raw struct {
+		layerAlias
+		Data   json.RawMessage `json:"data"`
+		Layers []TilemapLayer  `json:"layers"` // of a group
+		Chunks []struct {
+			X      int             `json:"x"`
+			Y      int             `json:"y"`
+			Width  int             `json:"width"`
+			Height int             `json:"height"`
+			Data   json.RawMessage `json:"data"`
+		} `json:"chunks"`
+	}
+	if err := json.Unmarshal(contents, &raw); err != nil {
+		return err
+	}
+
+	*l = TilemapLayer(raw.layerAlias)
+	l.layers = raw.Layers
+
+	if len(raw.Chunks) > 0 {
+		chunks := make([]TilemapChunk, 0, len(raw.Chunks))
+		for _, rawChunk := range raw.Chunks {
+			data, err := decodeLayerData(rawChunk.Data, l.Encoding, l.Compression)
+			if err != nil {
+				return fmt.Errorf("layer %q, chunk %d,%d: %w", l.Name, rawChunk.X, rawChunk.Y, err)
+			}
+			chunks = append(chunks, TilemapChunk{
+				X:      rawChunk.X,
+				Y:      rawChunk.Y,
+				Width:  rawChunk.Width,
+				Height: rawChunk.Height,
+				Data:   data,
+			})
+		}
+		return l.mergeChunks(chunks)
+	}
+
+	if len(raw.Data) == 0 {
+		return nil
+	}
+
+	data, err := decodeLayerData(raw.Data, l.Encoding, l.Compression)
+	if err != nil {
+		return fmt.Errorf("layer %q: %w", l.Name, err)
+	}
+	l.Data = data
+
+	return nil
+}
+
+func decodeLayerData(raw json.RawMessage, encoding, compression string) ([]uint32, error) {
+	switch encoding {
+	case "", "csv":
+		var data []uint32
+		if err := json.Unmarshal(raw, &data); err != nil {
+			return nil, err
+		}
+		return data, nil
+
+	case "base64":
+		var encoded string
+		if err := json.Unmarshal(raw, &encoded); err != nil {
+			return nil, err
+		}
+		return decodeBase64(encoded, compression)
+	}
+
+	return nil, fmt.Errorf("unknown layer encoding %q", encoding)
+}
+
+// decodeBase64 decodes Tiled's base64 layer format: little-endian uint32 GIDs,
+// optionally compressed before encoding.
+func decodeBase64(encoded, compression string) ([]uint32, error) {
+	compressed, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, err
+	}
+
+	var reader io.Reader
+	switch compression {
+	case "":
+		reader = bytes.NewReader(compressed)
+	case "zlib":
+		zlibReader, err := zlib.NewReader(bytes.NewReader(compressed))
+		if err != nil {
+			return nil, err
+		}
+		defer zlibReader.Close()
+		reader = zlibReader
+	case "gzip":
+		gzipReader, err := gzip.NewReader(bytes.NewReader(compressed))
+		if err != nil {
+			return nil, err
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+	case "zstd":
+		return nil, ErrZstdUnsupported
+	default:
+		return nil, fmt.Errorf("unknown layer compression %q", compression)
+	}
+
+	contents, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	if len(contents)%4 != 0 {
+		return nil, fmt.Errorf("layer data is %d bytes, not a multiple of 4", len(contents))
+	}
+
+	data := make([]uint32, len(contents)/4)
+	for i := range data {
+		data[i] = binary.LittleEndian.Uint32(contents[i*4:])
+	}
+
+	return data, nil
+}
@@ -0,0 +1,191 @@
+package tilemap
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+// encode packs gids the way Tiled does: little-endian uint32s, compressed, then base64.
+func encode(t *testing.T, gids []uint32, compression string) string {
+	t.Helper()
+	raw := make([]byte, 4*len(gids))
+	for i, gid := range gids {
+		binary.LittleEndian.PutUint32(raw[i*4:], gid)
+	}
+
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	switch compression {
+	case "":
+		buf.Write(raw)
+	case "zlib":
+		w = zlib.NewWriter(&buf)
+	case "gzip":
+		w = gzip.NewWriter(&buf)
+	default:
+		buf.Write(raw) // 압축 해제까지 가지 않는 경우
+	}
+	if w != nil {
+		w.Write(raw)
+		w.Close()
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+func TestDecodeBase64(t *testing.T) {
+	gids := []uint32{1, 0, 0x80000002, 513}
+	tests := []struct {
+		name        string
+		encoded     string
+		compression string
+		want        []uint32
+		wantErr     bool
+	}{
+		{"uncompressed", encode(t, gids, ""), "", gids, false},
+		{"zlib", encode(t, gids, "zlib"), "zlib", gids, false},
+		{"gzip", encode(t, gids, "gzip"), "gzip", gids, false},
+		{"surrounding whitespace", "\n   " + encode(t, gids, "zlib") + "\n  ", "zlib", gids, false},
+		{"empty", "", "", []uint32{}, false},
+		{"zstd", encode(t, gids, ""), "zstd", nil, true},
+		{"unknown compression", encode(t, gids, ""), "lz4", nil, true},
+		{"not base64", "!!!", "", nil, true},
+		{"not compressed", encode(t, gids, ""), "zlib", nil, true},
+		{"partial gid", base64.StdEncoding.EncodeToString([]byte{1, 0, 0}), "", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeBase64(tt.encoded, tt.compression)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, want error %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := decodeBase64(encode(t, gids, ""), "zstd"); !errors.Is(err, ErrZstdUnsupported) {
+		t.Errorf("zstd: err = %v, want ErrZstdUnsupported", err)
+	}
+}
+
+func TestUnmarshalLayer(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		want    []uint32
+		bounds  [4]int // StartX, StartY, Width, Height
+		wantErr bool
+	}{
+		{
+			name:   "csv",
+			json:   `{"type":"tilelayer","width":2,"height":2,"data":[1,2,3,4]}`,
+			want:   []uint32{1, 2, 3, 4},
+			bounds: [4]int{0, 0, 2, 2},
+		},
+		{
+			name:   "base64 zlib",
+			json:   `{"type":"tilelayer","width":2,"height":1,"encoding":"base64","compression":"zlib","data":"` + encode(t, []uint32{5, 6}, "zlib") + `"}`,
+			want:   []uint32{5, 6},
+			bounds: [4]int{0, 0, 2, 1},
+		},
+		{
+			// 무한 맵: 청크 사이의 빈 곳은 0으로 채운다
+			name: "chunks",
+			json: `{"type":"tilelayer","chunks":[
+				{"x":-2,"y":-1,"width":2,"height":1,"data":[1,2]},
+				{"x":1,"y":0,"width":1,"height":1,"data":[3]}]}`,
+			want: []uint32{
+				1, 2, 0, 0,
+				0, 0, 0, 3,
+			},
+			bounds: [4]int{-2, -1, 4, 2},
+		},
+		{
+			name: "base64 chunks",
+			json: `{"type":"tilelayer","encoding":"base64","compression":"gzip","chunks":[
+				{"x":0,"y":0,"width":1,"height":2,"data":"` + encode(t, []uint32{7, 8}, "gzip") + `"}]}`,
+			want:   []uint32{7, 8},
+			bounds: [4]int{0, 0, 1, 2},
+		},
+		{
+			name:    "chunk size mismatch",
+			json:    `{"type":"tilelayer","chunks":[{"x":0,"y":0,"width":2,"height":2,"data":[1,2,3]}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "unknown encoding",
+			json:    `{"type":"tilelayer","width":1,"height":1,"encoding":"hex","data":"01"}`,
+			wantErr: true,
+		},
+		{
+			name:   "object group has no data",
+			json:   `{"type":"objectgroup","objects":[]}`,
+			want:   nil,
+			bounds: [4]int{0, 0, 0, 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var layer TilemapLayer
+			err := json.Unmarshal([]byte(tt.json), &layer)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, want error %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !slices.Equal(layer.Data, tt.want) {
+				t.Errorf("data = %v, want %v", layer.Data, tt.want)
+			}
+			if got := [4]int{layer.StartX, layer.StartY, layer.Width, layer.Height}; got != tt.bounds {
+				t.Errorf("bounds = %v, want %v", got, tt.bounds)
+			}
+		})
+	}
+}
+
+func TestLoadTMXData(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []uint32
+	}{
+		{"csv", `<data encoding="csv">
+1,2,
+3,4
+</data>`, []uint32{1, 2, 3, 4}},
+		{"base64 gzip", `<data encoding="base64" compression="gzip">` + encode(t, []uint32{1, 2, 3, 4}, "gzip") + `</data>`, []uint32{1, 2, 3, 4}},
+		{"xml tiles", `<data><tile gid="1"/><tile/><tile gid="3"/><tile gid="4"/></data>`, []uint32{1, 0, 3, 4}},
+		{"chunks", `<data encoding="csv"><chunk x="0" y="-1" width="2" height="1">1,2</chunk><chunk x="0" y="0" width="2" height="1">3,4</chunk></data>`, []uint32{1, 2, 3, 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := fstest.MapFS{
+				"maps/test.tmx": {Data: []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<map tilewidth="16" tileheight="16">
+ <layer name="ground" width="2" height="2">` + tt.data + `</layer>
+</map>`)},
+			}
+			tm, err := Load(fsys, "maps/test.tmx")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(tm.Layers) != 1 {
+				t.Fatalf("got %d layers, want 1", len(tm.Layers))
+			}
+			if got := tm.Layers[0].Data; !slices.Equal(got, tt.want) {
+				t.Errorf("data = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package tilemap
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/FunctionPointerXDD/Trader/tileset"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Tiled stores the flip/rotation state of a tile in the high bits of its GID.
+const (
+	FlippedHorizontally uint32 = 0x80000000
+	FlippedVertically   uint32 = 0x40000000
+	FlippedDiagonally   uint32 = 0x20000000
+	RotatedHexagonal120 uint32 = 0x10000000
+
+	gidFlagsMask = FlippedHorizontally | FlippedVertically | FlippedDiagonally | RotatedHexagonal120
+)
+
+// Tile is a map cell resolved to its tileset image and orientation.
+type Tile struct {
+	Img      *ebiten.Image
+	Tileset  tileset.Tileset
+	Id       int // id local to Tileset
+	FlipH    bool
+	FlipV    bool
+	FlipDiag bool
+}
+
+// Size returns the size of the tile image once its flips are applied.
+func (t *Tile) Size() (int, int) {
+	if t.FlipDiag {
+		return t.Img.Bounds().Dy(), t.Img.Bounds().Dx()
+	}
+	return t.Img.Bounds().Dx(), t.Img.Bounds().Dy()
+}
+
+// GeoM returns the transform that applies the tile's flips while keeping the
+// flipped image's top-left corner at the origin.
+func (t *Tile) GeoM() ebiten.GeoM {
+	geoM := ebiten.GeoM{}
+	w := float64(t.Img.Bounds().Dx())
+	h := float64(t.Img.Bounds().Dy())
+
+	// Tiled applies the diagonal flip (swap x and y) first, then horizontal, then vertical.
+	if t.FlipDiag {
+		geoM.SetElement(0, 0, 0)
+		geoM.SetElement(0, 1, 1)
+		geoM.SetElement(1, 0, 1)
+		geoM.SetElement(1, 1, 0)
+		w, h = h, w
+	}
+	if t.FlipH {
+		geoM.Scale(-1, 1)
+		geoM.Translate(w, 0)
+	}
+	if t.FlipV {
+		geoM.Scale(1, -1)
+		geoM.Translate(0, h)
+	}
+	return geoM
+}
+
+type tilesetEntry struct {
+	firstGid int
+	tileset  tileset.Tileset
+}
+
+// Tilesets resolves GIDs to the tileset whose firstgid range contains them.
+type Tilesets struct {
+	entries []tilesetEntry // sorted by firstGid
+}
+
+func NewTilesets() *Tilesets {
+	return &Tilesets{
+		entries: make([]tilesetEntry, 0),
+	}
+}
+
+// Dispose frees the images of every tileset. The tilesets must not be drawn afterwards.
+func (t *Tilesets) Dispose() {
+	for _, entry := range t.entries {
+		entry.tileset.Dispose()
+	}
+}
+
+func (t *Tilesets) Add(firstGid int, ts tileset.Tileset) {
+	t.entries = append(t.entries, tilesetEntry{firstGid, ts})
+	sort.SliceStable(t.entries, func(i, j int) bool {
+		return t.entries[i].firstGid < t.entries[j].firstGid
+	})
+}
+
+// Tile resolves a raw GID from layer data, flip bits included.
+// The empty GID 0 is not a tile and returns an error like any other unknown GID.
+func (t *Tilesets) Tile(gid uint32) (*Tile, error) {
+	return t.resolve(gid, -1)
+}
+
+// AnimatedTile resolves a raw GID like Tile, showing the frame of the tile's
+// animation elapsedMs after the map started animating.
+func (t *Tilesets) AnimatedTile(gid uint32, elapsedMs int) (*Tile, error) {
+	return t.resolve(gid, elapsedMs)
+}
+
+// resolve looks gid up, using the animation frame at elapsedMs when it isn't negative.
+func (t *Tilesets) resolve(gid uint32, elapsedMs int) (*Tile, error) {
+	id := int(gid &^ gidFlagsMask)
+
+	// index of the last tileset starting at or before id
+	index := sort.Search(len(t.entries), func(i int) bool {
+		return t.entries[i].firstGid > id
+	}) - 1
+	if id == 0 || index < 0 {
+		return nil, fmt.Errorf("unknown gid %d", id)
+	}
+
+	entry := t.entries[index]
+	localId := id - entry.firstGid
+	if elapsedMs >= 0 {
+		localId = entry.tileset.Frame(localId, elapsedMs)
+	}
+
+	img, err := entry.tileset.Img(localId)
+	if err != nil {
+		return nil, fmt.Errorf("gid %d: %w", id, err)
+	}
+
+	return &Tile{
+		Img:      img,
+		Tileset:  entry.tileset,
+		Id:       localId,
+		FlipH:    gid&FlippedHorizontally != 0,
+		FlipV:    gid&FlippedVertically != 0,
+		FlipDiag: gid&FlippedDiagonally != 0,
+	}, nil
+}
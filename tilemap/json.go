@@ -0,0 +1,27 @@
+package tilemap
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+)
+
+func NewTilemapJSON(fsys fs.FS, name string) (*Tilemap, error) {
+	contents, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var tilemap Tilemap
+	err = json.Unmarshal(contents, &tilemap)
+	if err != nil {
+		return nil, err
+	}
+	tilemap.path = name
+	tilemap.Layers, err = flattenLayers(tilemap.Layers)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	return &tilemap, nil
+}
@@ -0,0 +1,34 @@
+package tilemap
+
+import "github.com/FunctionPointerXDD/Trader/properties"
+
+type TilemapObject struct {
+	Id         int                   `json:"id"`
+	Name       string                `json:"name"`
+	Class      string                `json:"class"`
+	Type       string                `json:"type"` // Tiled <= 1.8, and 1.10+ in compatibility mode, saves the class here
+	Gid        int                   `json:"gid"`
+	X          float64               `json:"x"`
+	Y          float64               `json:"y"`
+	Width      float64               `json:"width"`
+	Height     float64               `json:"height"`
+	Point      bool                  `json:"point"`
+	Properties properties.Properties `json:"properties"`
+}
+
+// ClassName returns the class assigned to the object in Tiled.
+func (o *TilemapObject) ClassName() string {
+	if o.Class != "" {
+		return o.Class
+	}
+	return o.Type
+}
+
+// Position returns the top-left corner of the object in map pixels.
+// Tile objects are anchored at their bottom-left corner in Tiled, so they are shifted up by their height.
+func (o *TilemapObject) Position() (float64, float64) {
+	if o.Gid != 0 {
+		return o.X, o.Y - o.Height
+	}
+	return o.X, o.Y
+}
@@ -1,53 +1,252 @@
 package tilemap
 
 import (
-	"encoding/json"
-	"os"
+	"fmt"
+	"image"
+	"io/fs"
 	"path"
+	"strings"
 
+	"github.com/FunctionPointerXDD/Trader/properties"
 	"github.com/FunctionPointerXDD/Trader/tileset"
 )
 
-type TilemapLayerJSON struct {
-	Data   []int  `json:"data"`
-	Width  int    `json:"width"`
-	Height int    `json:"height"`
-	Name   string `json:"name"`
+const (
+	TileLayer   = "tilelayer"
+	ObjectGroup = "objectgroup"
+	Group       = "group"
+	ImageLayer  = "imagelayer"
+)
+
+// Tilemap is the format-neutral map model, filled in by the JSON and TMX loaders.
+type Tilemap struct {
+	Layers     []TilemapLayer        `json:"layers"`
+	Tilesets   []TilesetRef          `json:"tilesets"`
+	TileWidth  int                   `json:"tilewidth"`
+	TileHeight int                   `json:"tileheight"`
+	Properties properties.Properties `json:"properties"`
+
+	path string // where the map was loaded from, tileset sources are relative to it
+}
+
+type TilemapLayer struct {
+	Data        []uint32              `json:"-"` // decoded from "data" or "chunks" by UnmarshalJSON
+	StartX      int                   `json:"-"` // tile coordinates of Data[0], negative for infinite maps grown up or left
+	StartY      int                   `json:"-"`
+	Width       int                   `json:"width"`
+	Height      int                   `json:"height"`
+	Name        string                `json:"name"`
+	Type        string                `json:"type"`
+	Encoding    string                `json:"encoding"`
+	Compression string                `json:"compression"`
+	Objects     []TilemapObject       `json:"objects"`
+	Properties  properties.Properties `json:"properties"`
+
+	layers []TilemapLayer // of a group, until flattenLayers replaces the group with them
+}
+
+// TilemapChunk is a block of tiles of an infinite map, positioned in tiles.
+type TilemapChunk struct {
+	X, Y          int
+	Width, Height int
+	Data          []uint32
 }
 
-type TilemapJSON struct {
-	Layers   []TilemapLayerJSON `json:"layers"`
-	Tilesets []map[string]any   `json:"tilesets"`
+// TilesetRef points at an external tileset file, relative to the maps directory.
+type TilesetRef struct {
+	FirstGid int    `json:"firstgid" xml:"firstgid,attr"`
+	Source   string `json:"source" xml:"source,attr"`
 }
 
-func (t *TilemapJSON) GenTilesets() ([]tileset.Tileset, error) {
+// Load reads a map saved by Tiled from fsys, picking the format from the file extension.
+func Load(fsys fs.FS, name string) (*Tilemap, error) {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".json", ".tmj":
+		return NewTilemapJSON(fsys, name)
+	case ".tmx":
+		return NewTilemapTMX(fsys, name)
+	}
+	return nil, fmt.Errorf("%s: unknown map format", name)
+}
+
+// Clone returns a copy of the map whose tile data can be changed without affecting t.
+func (t *Tilemap) Clone() *Tilemap {
+	clone := *t
+	clone.Layers = make([]TilemapLayer, len(t.Layers))
+	for i, layer := range t.Layers {
+		layer.Data = append([]uint32(nil), layer.Data...)
+		clone.Layers[i] = layer
+	}
+	return &clone
+}
 
-	tilesets := make([]tileset.Tileset, 0)
+// Bounds returns the area covered by the tile layers, in tiles.
+func (t *Tilemap) Bounds() image.Rectangle {
+	bounds := image.Rectangle{}
+	for _, layer := range t.Layers {
+		if layer.Type != TileLayer {
+			continue
+		}
+		bounds = bounds.Union(layer.Bounds())
+	}
+	return bounds
+}
+
+// PixelBounds returns the area covered by the tile layers, in pixels.
+func (t *Tilemap) PixelBounds() image.Rectangle {
+	bounds := t.Bounds()
+	return image.Rect(
+		bounds.Min.X*t.TileWidth,
+		bounds.Min.Y*t.TileHeight,
+		bounds.Max.X*t.TileWidth,
+		bounds.Max.Y*t.TileHeight,
+	)
+}
+
+// Bounds returns the area covered by the layer's Data, in tiles.
+func (l *TilemapLayer) Bounds() image.Rectangle {
+	return image.Rect(l.StartX, l.StartY, l.StartX+l.Width, l.StartY+l.Height)
+}
+
+// flattenLayers replaces group layers with the layers inside them, recursively,
+// keeping the drawing order. Image layers are rejected rather than left undrawn.
+func flattenLayers(layers []TilemapLayer) ([]TilemapLayer, error) {
+	flat := make([]TilemapLayer, 0, len(layers))
+	for _, layer := range layers {
+		switch layer.Type {
+		case Group:
+			children, err := flattenLayers(layer.layers)
+			if err != nil {
+				return nil, fmt.Errorf("group %q: %w", layer.Name, err)
+			}
+			flat = append(flat, children...)
+		case ImageLayer:
+			return nil, fmt.Errorf("layer %q: image layers are not supported", layer.Name)
+		default:
+			flat = append(flat, layer)
+		}
+	}
+	return flat, nil
+}
+
+// mergeChunks lays the chunks of an infinite map out in a single Data array
+// covering their bounding box, so the rest of the game only deals with one shape of layer.
+func (l *TilemapLayer) mergeChunks(chunks []TilemapChunk) error {
+	bounds := image.Rectangle{}
+	for _, chunk := range chunks {
+		if len(chunk.Data) != chunk.Width*chunk.Height {
+			return fmt.Errorf("layer %q, chunk %d,%d has %d tiles, expected %dx%d", l.Name, chunk.X, chunk.Y, len(chunk.Data), chunk.Width, chunk.Height)
+		}
+		bounds = bounds.Union(image.Rect(chunk.X, chunk.Y, chunk.X+chunk.Width, chunk.Y+chunk.Height))
+	}
+
+	l.StartX = bounds.Min.X
+	l.StartY = bounds.Min.Y
+	l.Width = bounds.Dx()
+	l.Height = bounds.Dy()
+	l.Data = make([]uint32, l.Width*l.Height)
+
+	for _, chunk := range chunks {
+		for row := 0; row < chunk.Height; row++ {
+			dst := (chunk.Y-l.StartY+row)*l.Width + chunk.X - l.StartX
+			copy(l.Data[dst:dst+chunk.Width], chunk.Data[row*chunk.Width:(row+1)*chunk.Width])
+		}
+	}
 
-	for _, tilesetData := range t.Tilesets {
-		tilesetPath := path.Join("assets/maps/", tilesetData["source"].(string))
-		tileset, err := tileset.NewTileset(tilesetPath, int(tilesetData["firstgid"].(float64)))
+	return nil
+}
+
+// Objects returns the objects of every object group layer, in layer order.
+func (t *Tilemap) Objects() []TilemapObject {
+	objects := make([]TilemapObject, 0)
+	for _, layer := range t.Layers {
+		if layer.Type != ObjectGroup {
+			continue
+		}
+		objects = append(objects, layer.Objects...)
+	}
+	return objects
+}
+
+// TilesetSources are the map's tilesets, decoded but not yet built into ebiten images.
+type TilesetSources struct {
+	firstGids []int
+	sources   []*tileset.Source
+}
+
+// DecodeTilesets reads the map's external tilesets and their images from fsys,
+// relative to the map. It does not create ebiten images, so it can run on a
+// loading goroutine.
+func (t *Tilemap) DecodeTilesets(fsys fs.FS) (*TilesetSources, error) {
+
+	decoded := &TilesetSources{}
+
+	for _, ref := range t.Tilesets {
+		if ref.Source == "" {
+			return nil, fmt.Errorf("tileset at firstgid %d is embedded in the map, only external tilesets are supported", ref.FirstGid)
+		}
+		// Standardize separators for cross-platform compatibility
+		tilesetPath := path.Join(path.Dir(t.path), strings.ReplaceAll(ref.Source, "\\", "/"))
+		source, err := tileset.Decode(fsys, tilesetPath)
 		if err != nil {
 			return nil, err
 		}
 
-		tilesets = append(tilesets, tileset)
+		decoded.firstGids = append(decoded.firstGids, ref.FirstGid)
+		decoded.sources = append(decoded.sources, source)
 	}
 
-	return tilesets, nil
+	return decoded, nil
 }
 
-func NewTilemapJSON(filepath string) (*TilemapJSON, error) {
-	contents, err := os.ReadFile(filepath)
-	if err != nil {
-		return nil, err
+// Files returns every tileset file and image the sources were decoded from.
+func (d *TilesetSources) Files() []string {
+	files := make([]string, 0, len(d.sources))
+	for _, source := range d.sources {
+		files = append(files, source.Files()...)
+	}
+	return files
+}
+
+// Build creates the tilesets' ebiten images. Call it on the main thread.
+func (d *TilesetSources) Build() (*Tilesets, error) {
+
+	tilesets := NewTilesets()
+
+	for i, source := range d.sources {
+		tileset, err := source.Build()
+		if err != nil {
+			return nil, err
+		}
+
+		tilesets.Add(d.firstGids[i], tileset)
 	}
 
-	var tilemapJSON TilemapJSON
-	err = json.Unmarshal(contents, &tilemapJSON)
+	return tilesets, nil
+}
+
+func (t *Tilemap) GenTilesets(fsys fs.FS) (*Tilesets, error) {
+	decoded, err := t.DecodeTilesets(fsys)
 	if err != nil {
 		return nil, err
 	}
+	return decoded.Build()
+}
 
-	return &tilemapJSON, nil
+// Validate checks that every tile in the tile layers resolves to a tileset image.
+func (t *Tilemap) Validate(tilesets *Tilesets) error {
+	for _, layer := range t.Layers {
+		if layer.Type == TileLayer && len(layer.Data) != layer.Width*layer.Height {
+			return fmt.Errorf("layer %q has %d tiles, expected %dx%d", layer.Name, len(layer.Data), layer.Width, layer.Height)
+		}
+		for index, gid := range layer.Data {
+			if gid == 0 {
+				continue
+			}
+			if _, err := tilesets.Tile(gid); err != nil {
+				return fmt.Errorf("layer %q, tile %d: %w", layer.Name, index, err)
+			}
+		}
+	}
+	return nil
 }
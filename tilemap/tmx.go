@@ -0,0 +1,216 @@
+package tilemap
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+
+	"github.com/FunctionPointerXDD/Trader/properties"
+)
+
+type tmxMap struct {
+	TileWidth  int                   `xml:"tilewidth,attr"`
+	TileHeight int                   `xml:"tileheight,attr"`
+	Properties properties.Properties `xml:"properties"`
+	Tilesets   []TilesetRef          `xml:"tileset"`
+	Layers     []tmxLayer            `xml:",any"` // <layer>, <objectgroup> and <group>, kept in document order
+}
+
+type tmxLayer struct {
+	XMLName    xml.Name
+	Name       string                `xml:"name,attr"`
+	Properties properties.Properties `xml:"properties"`
+	Width      int                   `xml:"width,attr"`
+	Height     int                   `xml:"height,attr"`
+	Data       tmxData               `xml:"data"`
+	Objects    []tmxObject           `xml:"object"`
+	Layers     []tmxLayer            `xml:",any"` // of a <group>
+}
+
+type tmxData struct {
+	Encoding    string        `xml:"encoding,attr"`
+	Compression string        `xml:"compression,attr"`
+	Text        string        `xml:",chardata"`
+	Tiles       []tmxDataTile `xml:"tile"`
+	Chunks      []tmxChunk    `xml:"chunk"`
+}
+
+type tmxChunk struct {
+	X      int           `xml:"x,attr"`
+	Y      int           `xml:"y,attr"`
+	Width  int           `xml:"width,attr"`
+	Height int           `xml:"height,attr"`
+	Text   string        `xml:",chardata"`
+	Tiles  []tmxDataTile `xml:"tile"`
+}
+
+type tmxDataTile struct {
+	Gid uint32 `xml:"gid,attr"`
+}
+
+type tmxObject struct {
+	Id         int                   `xml:"id,attr"`
+	Name       string                `xml:"name,attr"`
+	Class      string                `xml:"class,attr"`
+	Type       string                `xml:"type,attr"`
+	Gid        int                   `xml:"gid,attr"`
+	X          float64               `xml:"x,attr"`
+	Y          float64               `xml:"y,attr"`
+	Width      float64               `xml:"width,attr"`
+	Height     float64               `xml:"height,attr"`
+	Point      *struct{}             `xml:"point"`
+	Properties properties.Properties `xml:"properties"`
+}
+
+func NewTilemapTMX(fsys fs.FS, name string) (*Tilemap, error) {
+	contents, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var tmx tmxMap
+	err = xml.Unmarshal(contents, &tmx)
+	if err != nil {
+		return nil, err
+	}
+
+	tilemap := Tilemap{
+		Layers:     make([]TilemapLayer, 0, len(tmx.Layers)),
+		Tilesets:   tmx.Tilesets,
+		TileWidth:  tmx.TileWidth,
+		TileHeight: tmx.TileHeight,
+		Properties: tmx.Properties,
+		path:       name,
+	}
+
+	tilemap.Layers, err = appendTMXLayers(tilemap.Layers, tmx.Layers)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	return &tilemap, nil
+}
+
+// appendTMXLayers converts tmxLayers and appends them to layers, replacing groups
+// with the layers inside them like flattenLayers does for JSON maps.
+func appendTMXLayers(layers []TilemapLayer, tmxLayers []tmxLayer) ([]TilemapLayer, error) {
+	for _, tmxLayer := range tmxLayers {
+		switch tmxLayer.XMLName.Local {
+		case "layer":
+			layer, err := tmxLayer.tileLayer()
+			if err != nil {
+				return nil, err
+			}
+			layers = append(layers, layer)
+		case ObjectGroup:
+			layers = append(layers, tmxLayer.objectGroup())
+		case Group:
+			var err error
+			layers, err = appendTMXLayers(layers, tmxLayer.Layers)
+			if err != nil {
+				return nil, fmt.Errorf("group %q: %w", tmxLayer.Name, err)
+			}
+		case ImageLayer:
+			return nil, fmt.Errorf("layer %q: image layers are not supported", tmxLayer.Name)
+		}
+	}
+	return layers, nil
+}
+
+func (l *tmxLayer) tileLayer() (TilemapLayer, error) {
+	layer := TilemapLayer{
+		Width:       l.Width,
+		Height:      l.Height,
+		Name:        l.Name,
+		Type:        TileLayer,
+		Properties:  l.Properties,
+		Encoding:    l.Data.Encoding,
+		Compression: l.Data.Compression,
+	}
+
+	if len(l.Data.Chunks) > 0 {
+		chunks := make([]TilemapChunk, 0, len(l.Data.Chunks))
+		for _, tmxChunk := range l.Data.Chunks {
+			data, err := l.Data.decode(tmxChunk.Text, tmxChunk.Tiles)
+			if err != nil {
+				return layer, fmt.Errorf("layer %q, chunk %d,%d: %w", l.Name, tmxChunk.X, tmxChunk.Y, err)
+			}
+			chunks = append(chunks, TilemapChunk{
+				X:      tmxChunk.X,
+				Y:      tmxChunk.Y,
+				Width:  tmxChunk.Width,
+				Height: tmxChunk.Height,
+				Data:   data,
+			})
+		}
+		return layer, layer.mergeChunks(chunks)
+	}
+
+	data, err := l.Data.decode(l.Data.Text, l.Data.Tiles)
+	if err != nil {
+		return layer, fmt.Errorf("layer %q: %w", l.Name, err)
+	}
+	layer.Data = data
+
+	return layer, nil
+}
+
+// decode reads the tiles of the layer, or of one of its chunks, in the layer's encoding.
+func (d *tmxData) decode(text string, tiles []tmxDataTile) ([]uint32, error) {
+	switch d.Encoding {
+	case "":
+		// deprecated XML format, one <tile> element per cell
+		data := make([]uint32, len(tiles))
+		for i, tile := range tiles {
+			data[i] = tile.Gid
+		}
+		return data, nil
+
+	case "csv":
+		fields := strings.Split(strings.TrimSpace(text), ",")
+		data := make([]uint32, len(fields))
+		for i, field := range fields {
+			gid, err := strconv.ParseUint(strings.TrimSpace(field), 10, 32)
+			if err != nil {
+				return nil, err
+			}
+			data[i] = uint32(gid)
+		}
+		return data, nil
+
+	case "base64":
+		return decodeBase64(text, d.Compression)
+	}
+
+	return nil, fmt.Errorf("unknown layer encoding %q", d.Encoding)
+}
+
+func (l *tmxLayer) objectGroup() TilemapLayer {
+	layer := TilemapLayer{
+		Name:       l.Name,
+		Type:       ObjectGroup,
+		Properties: l.Properties,
+		Objects:    make([]TilemapObject, 0, len(l.Objects)),
+	}
+
+	for _, o := range l.Objects {
+		object := TilemapObject{
+			Id:         o.Id,
+			Name:       o.Name,
+			Class:      o.Class,
+			Type:       o.Type,
+			Gid:        o.Gid,
+			X:          o.X,
+			Y:          o.Y,
+			Width:      o.Width,
+			Height:     o.Height,
+			Point:      o.Point != nil,
+			Properties: o.Properties,
+		}
+		layer.Objects = append(layer.Objects, object)
+	}
+
+	return layer
+}
@@ -0,0 +1,76 @@
+package tileset
+
+import "fmt"
+
+type TileFrame struct {
+	Id       int
+	Duration int // milliseconds
+}
+
+// TileAnimation cycles a tile through other tiles of the same tileset, as authored in Tiled.
+type TileAnimation struct {
+	frames []TileFrame
+	total  int // duration of one loop, milliseconds
+}
+
+// Frame returns the id of the tile shown elapsedMs into the animation.
+func (a *TileAnimation) Frame(elapsedMs int) int {
+	elapsedMs %= a.total
+	for _, frame := range a.frames {
+		if elapsedMs < frame.Duration {
+			return frame.Id
+		}
+		elapsedMs -= frame.Duration
+	}
+	return a.frames[len(a.frames)-1].Id
+}
+
+// tileAnimations is embedded by the tilesets to implement Frame and Animated.
+type tileAnimations struct {
+	animations map[int]*TileAnimation
+}
+
+func (t *tileAnimations) Frame(id int, elapsedMs int) int {
+	animation, ok := t.animations[id]
+	if !ok {
+		return id
+	}
+	return animation.Frame(elapsedMs)
+}
+
+func (t *tileAnimations) Animated(id int) bool {
+	_, ok := t.animations[id]
+	return ok
+}
+
+// newTileAnimations collects the tile animations of a tileset, checking every frame
+// points at a tile that tileset can draw.
+func newTileAnimations(tilesetData *TilesetData, tileset Tileset) (tileAnimations, error) {
+	t := tileAnimations{
+		animations: make(map[int]*TileAnimation),
+	}
+
+	for _, tileData := range tilesetData.Tiles {
+		if len(tileData.Animation) == 0 {
+			continue
+		}
+
+		animation := TileAnimation{
+			frames: make([]TileFrame, 0, len(tileData.Animation)),
+		}
+		for _, frameData := range tileData.Animation {
+			if frameData.Duration <= 0 {
+				return t, fmt.Errorf("tile %d: animation frame duration must be positive", tileData.Id)
+			}
+			if _, err := tileset.Img(frameData.TileId); err != nil {
+				return t, fmt.Errorf("tile %d: animation frame: %w", tileData.Id, err)
+			}
+			animation.frames = append(animation.frames, TileFrame{frameData.TileId, frameData.Duration})
+			animation.total += frameData.Duration
+		}
+
+		t.animations[tileData.Id] = &animation
+	}
+
+	return t, nil
+}
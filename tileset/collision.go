@@ -0,0 +1,95 @@
+package tileset
+
+import (
+	"fmt"
+	"image"
+	"math"
+)
+
+// SolidProperty is the bool tile property that makes the whole tile block movement.
+const SolidProperty = "solid"
+
+// tileCollisions is embedded by the tilesets to implement Collision.
+type tileCollisions struct {
+	shapes map[int][]image.Rectangle
+}
+
+func (t *tileCollisions) Collision(id int) []image.Rectangle {
+	return t.shapes[id]
+}
+
+// newTileCollisions collects the collision shapes of a tileset: the whole tile image
+// for solid tiles, otherwise the shapes drawn in the tile's collision editor.
+func newTileCollisions(tilesetData *TilesetData, tileset Tileset) (tileCollisions, error) {
+	t := tileCollisions{
+		shapes: make(map[int][]image.Rectangle),
+	}
+
+	for _, tileData := range tilesetData.Tiles {
+		solid, err := tileData.solid()
+		if err != nil {
+			return t, err
+		}
+		if solid {
+			img, err := tileset.Img(tileData.Id)
+			if err != nil {
+				return t, err
+			}
+			t.shapes[tileData.Id] = []image.Rectangle{
+				image.Rect(0, 0, img.Bounds().Dx(), img.Bounds().Dy()),
+			}
+			continue
+		}
+
+		if tileData.ObjectGroup == nil {
+			continue
+		}
+		for _, shape := range tileData.ObjectGroup.Objects {
+			rect := shape.bounds()
+			if rect.Empty() {
+				continue
+			}
+			t.shapes[tileData.Id] = append(t.shapes[tileData.Id], rect)
+		}
+	}
+
+	return t, nil
+}
+
+func (t *TileData) solid() (bool, error) {
+	if !t.Properties.Has(SolidProperty) {
+		return false, nil
+	}
+	solid, err := t.Properties.Bool(SolidProperty)
+	if err != nil {
+		return false, fmt.Errorf("tile %d: %w", t.Id, err)
+	}
+	return solid, nil
+}
+
+// bounds returns the shape's bounding box, rounded outward to whole pixels.
+func (s *ShapeData) bounds() image.Rectangle {
+	if s.Point {
+		return image.Rectangle{}
+	}
+
+	minX, minY := s.X, s.Y
+	maxX, maxY := s.X+s.Width, s.Y+s.Height
+	if len(s.Polygon) > 0 {
+		minX, minY = math.Inf(1), math.Inf(1)
+		maxX, maxY = math.Inf(-1), math.Inf(-1)
+		for _, point := range s.Polygon {
+			minX = math.Min(minX, s.X+point.X)
+			minY = math.Min(minY, s.Y+point.Y)
+			maxX = math.Max(maxX, s.X+point.X)
+			maxY = math.Max(maxY, s.Y+point.Y)
+		}
+	}
+
+	return image.Rect(
+		int(math.Floor(minX)),
+		int(math.Floor(minY)),
+		int(math.Ceil(maxX)),
+		int(math.Ceil(maxY)),
+	)
+}
@@ -0,0 +1,190 @@
+package tileset
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/FunctionPointerXDD/Trader/properties"
+)
+
+// TilesetData is the format-neutral tileset description, filled in by the JSON and TSX loaders.
+type TilesetData struct {
+	Path        string      `json:"image"` // atlas image of a uniform tileset
+	ImageWidth  int         `json:"imagewidth"`
+	ImageHeight int         `json:"imageheight"`
+	Columns     int         `json:"columns"`
+	TileCount   int         `json:"tilecount"`
+	TileWidth   int         `json:"tilewidth"`
+	TileHeight  int         `json:"tileheight"`
+	Margin      int         `json:"margin"`  // pixels around the atlas border
+	Spacing     int         `json:"spacing"` // pixels between neighbouring tiles
+	Tiles       []*TileData `json:"tiles"`
+}
+
+type TileData struct {
+	Id          int                   `json:"id"`
+	Path        string                `json:"image"` // own image of an image collection tile
+	Width       int                   `json:"imagewidth"`
+	Height      int                   `json:"imageheight"`
+	Animation   []*FrameData          `json:"animation"`
+	Properties  properties.Properties `json:"properties"`
+	ObjectGroup *ShapeGroupData       `json:"objectgroup"` // collision shapes drawn in Tiled's collision editor
+}
+
+type ShapeGroupData struct {
+	Objects []*ShapeData `json:"objects"`
+}
+
+// ShapeData is a collision shape in pixels relative to the tile image's top-left corner.
+// Ellipses and polygons collide as their bounding box.
+type ShapeData struct {
+	X       float64      `json:"x"`
+	Y       float64      `json:"y"`
+	Width   float64      `json:"width"`
+	Height  float64      `json:"height"`
+	Point   bool         `json:"point"`
+	Polygon []*PointData `json:"polygon"`
+}
+
+type PointData struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+type FrameData struct {
+	TileId   int `json:"tileid" xml:"tileid,attr"`
+	Duration int `json:"duration" xml:"duration,attr"` // milliseconds
+}
+
+// IsImageCollection reports whether the tileset gives each tile its own image
+// instead of cutting tiles out of a single atlas image.
+func (t *TilesetData) IsImageCollection() bool {
+	return t.Path == ""
+}
+
+type tsxShape struct {
+	X       float64   `xml:"x,attr"`
+	Y       float64   `xml:"y,attr"`
+	Width   float64   `xml:"width,attr"`
+	Height  float64   `xml:"height,attr"`
+	Point   *struct{} `xml:"point"`
+	Polygon *struct {
+		Points string `xml:"points,attr"` // "x1,y1 x2,y2 ..."
+	} `xml:"polygon"`
+}
+
+type tsxImage struct {
+	Source string `xml:"source,attr"`
+	Width  int    `xml:"width,attr"`
+	Height int    `xml:"height,attr"`
+}
+
+type tsxTileset struct {
+	Columns    int       `xml:"columns,attr"`
+	TileCount  int       `xml:"tilecount,attr"`
+	TileWidth  int       `xml:"tilewidth,attr"`
+	TileHeight int       `xml:"tileheight,attr"`
+	Margin     int       `xml:"margin,attr"`
+	Spacing    int       `xml:"spacing,attr"`
+	Image      *tsxImage `xml:"image"`
+	Tiles      []struct {
+		Id         int                   `xml:"id,attr"`
+		Image      *tsxImage             `xml:"image"`
+		Animation  []*FrameData          `xml:"animation>frame"`
+		Properties properties.Properties `xml:"properties"`
+		Shapes     []*tsxShape           `xml:"objectgroup>object"`
+	} `xml:"tile"`
+}
+
+// LoadTilesetData reads a tileset saved by Tiled from fsys, picking the format from the file extension.
+func LoadTilesetData(fsys fs.FS, name string) (*TilesetData, error) {
+	contents, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(path.Ext(name)) {
+	case ".json", ".tsj":
+		var tilesetData TilesetData
+		err = json.Unmarshal(contents, &tilesetData)
+		if err != nil {
+			return nil, err
+		}
+		return &tilesetData, nil
+
+	case ".tsx":
+		var tsx tsxTileset
+		err = xml.Unmarshal(contents, &tsx)
+		if err != nil {
+			return nil, err
+		}
+		return tsx.tilesetData(), nil
+	}
+
+	return nil, fmt.Errorf("%s: unknown tileset format", name)
+}
+
+func (t *tsxTileset) tilesetData() *TilesetData {
+	tilesetData := TilesetData{
+		Columns:    t.Columns,
+		TileCount:  t.TileCount,
+		TileWidth:  t.TileWidth,
+		TileHeight: t.TileHeight,
+		Margin:     t.Margin,
+		Spacing:    t.Spacing,
+		Tiles:      make([]*TileData, 0, len(t.Tiles)),
+	}
+	if t.Image != nil {
+		tilesetData.Path = t.Image.Source
+		tilesetData.ImageWidth = t.Image.Width
+		tilesetData.ImageHeight = t.Image.Height
+	}
+
+	for _, tile := range t.Tiles {
+		tileData := TileData{
+			Id:         tile.Id,
+			Animation:  tile.Animation,
+			Properties: tile.Properties,
+		}
+		if len(tile.Shapes) > 0 {
+			tileData.ObjectGroup = &ShapeGroupData{
+				Objects: make([]*ShapeData, 0, len(tile.Shapes)),
+			}
+			for _, shape := range tile.Shapes {
+				tileData.ObjectGroup.Objects = append(tileData.ObjectGroup.Objects, shape.shapeData())
+			}
+		}
+		if tile.Image != nil {
+			tileData.Path = tile.Image.Source
+			tileData.Width = tile.Image.Width
+			tileData.Height = tile.Image.Height
+		}
+		tilesetData.Tiles = append(tilesetData.Tiles, &tileData)
+	}
+
+	return &tilesetData
+}
+
+func (s *tsxShape) shapeData() *ShapeData {
+	shapeData := ShapeData{
+		X:      s.X,
+		Y:      s.Y,
+		Width:  s.Width,
+		Height: s.Height,
+		Point:  s.Point != nil,
+	}
+	if s.Polygon != nil {
+		shapeData.Polygon = make([]*PointData, 0)
+		for _, pair := range strings.Fields(s.Polygon.Points) {
+			var point PointData
+			if _, err := fmt.Sscanf(pair, "%g,%g", &point.X, &point.Y); err == nil {
+				shapeData.Polygon = append(shapeData.Polygon, &point)
+			}
+		}
+	}
+	return &shapeData
+}
@@ -0,0 +1,25 @@
+package tileset
+
+import "github.com/FunctionPointerXDD/Trader/properties"
+
+// tileProperties is embedded by the tilesets to implement Properties.
+type tileProperties struct {
+	properties map[int]properties.Properties
+}
+
+func (t *tileProperties) Properties(id int) properties.Properties {
+	// a nil Properties behaves like an empty one
+	return t.properties[id]
+}
+
+func newTileProperties(tilesetData *TilesetData) tileProperties {
+	t := tileProperties{
+		properties: make(map[int]properties.Properties),
+	}
+	for _, tileData := range tilesetData.Tiles {
+		if len(tileData.Properties) > 0 {
+			t.properties[tileData.Id] = tileData.Properties
+		}
+	}
+	return t
+}
@@ -1,126 +1,246 @@
 package tileset
 
 import (
-	"encoding/json"
+	"fmt"
 	"image"
-	"os"
-	"path/filepath"
+	"io/fs"
+	"path"
 	"strings"
 
+	"github.com/FunctionPointerXDD/Trader/imagefile"
+	"github.com/FunctionPointerXDD/Trader/properties"
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 )
 
+// Tileset looks up tile images by their id local to the tileset (gid - firstgid).
 type Tileset interface {
-	Img(id int) *ebiten.Image
-}
-
-type UniformTilesetJSON struct {
-	Path string `json:"image"`
+	Img(id int) (*ebiten.Image, error)
+	// Frame returns the id to draw for id, elapsedMs into its animation.
+	// Tiles without an animation always return their own id.
+	Frame(id int, elapsedMs int) int
+	Animated(id int) bool
+	// Collision returns the tile's collision shapes, relative to the top-left corner of its image.
+	Collision(id int) []image.Rectangle
+	Properties(id int) properties.Properties
+	// Dispose frees the tile images. The tileset must not be drawn afterwards.
+	Dispose()
 }
 
 type UniformTileset struct {
-	img *ebiten.Image
-	gid int
+	tileAnimations
+	tileCollisions
+	tileProperties
+	img        *ebiten.Image
+	columns    int
+	tileCount  int
+	tileWidth  int
+	tileHeight int
+	margin     int
+	spacing    int
 }
 
-func (u *UniformTileset) Img(id int) *ebiten.Image {
-	id -= u.gid
+func (u *UniformTileset) Img(id int) (*ebiten.Image, error) {
+	if id < 0 || id >= u.tileCount {
+		return nil, fmt.Errorf("tile id %d out of range", id)
+	}
 
-	srcX := id % 22
-	srcY := id / 22
+	srcX := id % u.columns
+	srcY := id / u.columns
 
-	srcX *= 16
-	srcY *= 16
+	srcX = u.margin + srcX*(u.tileWidth+u.spacing)
+	srcY = u.margin + srcY*(u.tileHeight+u.spacing)
 
 	return u.img.SubImage(
 		image.Rect(
-			srcX, srcY, srcX+16, srcY+16,
+			srcX, srcY, srcX+u.tileWidth, srcY+u.tileHeight,
 		),
-	).(*ebiten.Image)
+	).(*ebiten.Image), nil
 
 }
 
-type TileJSON struct {
-	Id     int    `json:"id"`
-	Path   string `json:"image"`
-	Width  int    `json:"imagewidth"`
-	Height int    `json:"imageheight"`
+// Dispose implements [Tileset].
+func (u *UniformTileset) Dispose() {
+	u.img.Deallocate()
 }
 
-type DynTilesetJSON struct {
-	Tiles []*TileJSON `json:"tiles"`
+// newUniformTileset lays the atlas out from the tileset's geometry. Columns and tile
+// count are derived from the image size when the tileset doesn't store them.
+func newUniformTileset(img *ebiten.Image, tilesetData *TilesetData) (*UniformTileset, error) {
+	if tilesetData.TileWidth <= 0 || tilesetData.TileHeight <= 0 {
+		return nil, fmt.Errorf("invalid tile size %dx%d", tilesetData.TileWidth, tilesetData.TileHeight)
+	}
+
+	uniformTileset := UniformTileset{
+		img:        img,
+		columns:    tilesetData.Columns,
+		tileCount:  tilesetData.TileCount,
+		tileWidth:  tilesetData.TileWidth,
+		tileHeight: tilesetData.TileHeight,
+		margin:     tilesetData.Margin,
+		spacing:    tilesetData.Spacing,
+	}
+
+	imgWidth := img.Bounds().Dx() - 2*uniformTileset.margin + uniformTileset.spacing
+	imgHeight := img.Bounds().Dy() - 2*uniformTileset.margin + uniformTileset.spacing
+	if uniformTileset.columns <= 0 {
+		uniformTileset.columns = imgWidth / (uniformTileset.tileWidth + uniformTileset.spacing)
+	}
+	if uniformTileset.tileCount <= 0 {
+		uniformTileset.tileCount = uniformTileset.columns * (imgHeight / (uniformTileset.tileHeight + uniformTileset.spacing))
+	}
+	if uniformTileset.columns <= 0 {
+		return nil, fmt.Errorf("image is narrower than one %dpx tile", uniformTileset.tileWidth)
+	}
+
+	return &uniformTileset, nil
 }
 
+// DynTileset is an image collection tileset: every tile has its own image.
+// Tiled keeps the ids of deleted tiles unused, so ids may have gaps.
 type DynTileset struct {
-	imgs []*ebiten.Image
-	gid  int
+	tileAnimations
+	tileCollisions
+	tileProperties
+	imgs map[int]*ebiten.Image
+}
+
+func (d *DynTileset) Img(id int) (*ebiten.Image, error) {
+	img, ok := d.imgs[id]
+	if !ok {
+		return nil, fmt.Errorf("tile id %d not in tileset", id)
+	}
+
+	return img, nil
 }
 
-func (d *DynTileset) Img(id int) *ebiten.Image {
-	id -= d.gid
+// Dispose implements [Tileset].
+func (d *DynTileset) Dispose() {
+	for _, img := range d.imgs {
+		img.Deallocate()
+	}
+}
 
-	return d.imgs[id]
+// Source is a tileset whose data and images are loaded but not yet turned into
+// ebiten images. Decode is safe to call from any goroutine; Build must run on the
+// main thread.
+type Source struct {
+	path  string
+	data  *TilesetData
+	img   image.Image         // uniform tilesets
+	imgs  map[int]image.Image // image collections, by tile id
+	files []string
 }
 
-func NewTileset(path string, gid int) (Tileset, error) {
+func Decode(fsys fs.FS, path string) (*Source, error) {
 
-	contents, err := os.ReadFile(path)
+	tilesetData, err := LoadTilesetData(fsys, path)
 	if err != nil {
 		return nil, err
 	}
 
-	if strings.Contains(path, "buildings") {
-		// return dyn tileset
-		var dynTilesetJSON DynTilesetJSON
-		err = json.Unmarshal(contents, &dynTilesetJSON)
-		if err != nil {
-			return nil, err
-		}
+	source := &Source{
+		path:  path,
+		data:  tilesetData,
+		files: []string{path},
+	}
 
-		dynTileset := DynTileset{}
-		dynTileset.gid = gid
-		dynTileset.imgs = make([]*ebiten.Image, 0)
+	if tilesetData.IsImageCollection() {
+		source.imgs = make(map[int]image.Image, len(tilesetData.Tiles))
 
-		for _, tileJSON := range dynTilesetJSON.Tiles {
+		for _, tileData := range tilesetData.Tiles {
+			if tileData.Path == "" {
+				return nil, fmt.Errorf("%s: tile %d has no image", path, tileData.Id)
+			}
+			if _, ok := source.imgs[tileData.Id]; ok {
+				return nil, fmt.Errorf("%s: duplicate tile id %d", path, tileData.Id)
+			}
 
-			// Standardize separators for cross-platform compatibility
-			cleanPath := strings.ReplaceAll(tileJSON.Path, "\\", "/")
-			// Resolve path relative to the tileset JSON file location
-			tilesetDir := filepath.Dir(path)
-			fullPath := filepath.Join(tilesetDir, cleanPath)
-			img, _, err := ebitenutil.NewImageFromFile(fullPath)
+			imgPath := resolvePath(path, tileData.Path)
+			img, err := imagefile.Decode(fsys, imgPath)
 			if err != nil {
 				return nil, err
 			}
 
-			dynTileset.imgs = append(dynTileset.imgs, img)
+			source.imgs[tileData.Id] = img
+			source.files = append(source.files, imgPath)
+		}
+		return source, nil
+	}
+
+	imgPath := resolvePath(path, tilesetData.Path)
+	source.img, err = imagefile.Decode(fsys, imgPath)
+	if err != nil {
+		return nil, err
+	}
+	source.files = append(source.files, imgPath)
+	return source, nil
+}
+
+// Files returns the tileset file and the images it was decoded from.
+func (s *Source) Files() []string {
+	return s.files
+}
+
+func (s *Source) Build() (Tileset, error) {
+	path, tilesetData := s.path, s.data
+	var err error
+
+	if tilesetData.IsImageCollection() {
+		// return dyn tileset
+		dynTileset := DynTileset{}
+		dynTileset.imgs = make(map[int]*ebiten.Image, len(s.imgs))
+		for id, img := range s.imgs {
+			dynTileset.imgs[id] = ebiten.NewImageFromImage(img)
+		}
+
+		dynTileset.tileAnimations, err = newTileAnimations(tilesetData, &dynTileset)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		dynTileset.tileCollisions, err = newTileCollisions(tilesetData, &dynTileset)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
 		}
+		dynTileset.tileProperties = newTileProperties(tilesetData)
 
 		return &dynTileset, nil
 	}
 	//return uniform tileset
-	var uniformTilesetJSON UniformTilesetJSON
-	err = json.Unmarshal(contents, &uniformTilesetJSON)
+	uniformTileset, err := newUniformTileset(ebiten.NewImageFromImage(s.img), tilesetData)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%s: %w", path, err)
 	}
 
-	uniformTileset := UniformTileset{}
-
-	// Standardize separators for cross-platform compatibility
-	cleanPath := strings.ReplaceAll(uniformTilesetJSON.Path, "\\", "/")
+	uniformTileset.tileAnimations, err = newTileAnimations(tilesetData, uniformTileset)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	uniformTileset.tileCollisions, err = newTileCollisions(tilesetData, uniformTileset)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	uniformTileset.tileProperties = newTileProperties(tilesetData)
 
-	// Resolve path relative to the tileset JSON file location
-	tilesetDir := filepath.Dir(path)
-	fullPath := filepath.Join(tilesetDir, cleanPath)
+	return uniformTileset, nil
+}
 
-	img, _, err := ebitenutil.NewImageFromFile(fullPath)
+// NewTileset loads a tileset and its images from fsys on the calling goroutine.
+func NewTileset(fsys fs.FS, path string) (Tileset, error) {
+	source, err := Decode(fsys, path)
 	if err != nil {
 		return nil, err
 	}
-	uniformTileset.img = img
-	uniformTileset.gid = gid
+	return source.Build()
+}
 
-	return &uniformTileset, nil
+// resolvePath resolves an image path stored in a tileset relative to the tileset file location.
+func resolvePath(tilesetPath, imagePath string) string {
+	// Standardize separators for cross-platform compatibility
+	cleanPath := strings.ReplaceAll(imagePath, "\\", "/")
+
+	tilesetDir := path.Dir(tilesetPath)
+	return path.Join(tilesetDir, cleanPath)
 }
+
+var _ Tileset = (*UniformTileset)(nil)
+var _ Tileset = (*DynTileset)(nil)